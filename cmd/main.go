@@ -2,27 +2,49 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
+	"strings"
+	"time"
 
 	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
 	miniclient "github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
 )
 
+const usage = `Use:
+  kubectl task start <taskName> [-p name=value ...]
+  kubectl task cancel <taskRunName>
+  kubectl task describe <taskRunName>
+  kubectl task rerun <taskRunName>
+  kubectl task prune [--dry-run]`
+
 func main() {
 
-	if len(os.Args) < 3 {
-		fmt.Println("Use: kubectl task start <taskName>")
+	if len(os.Args) < 2 {
+		fmt.Println(usage)
 		os.Exit(1)
 	}
 
 	command := os.Args[1]
-	taskName := os.Args[2]
 
-	if command != "start" {
+	switch command {
+	case "start", "cancel", "describe", "rerun":
+		if len(os.Args) < 3 {
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+	case "prune":
+	default:
 		fmt.Println("Invalid Command")
+		fmt.Println(usage)
 		os.Exit(1)
 	}
 
@@ -42,6 +64,29 @@ func main() {
 
 	ctx := context.Background()
 
+	switch command {
+	case "start":
+		startTask(ctx, client, os.Args[2], os.Args[3:])
+	case "cancel":
+		cancelTaskRun(ctx, client, os.Args[2])
+	case "describe":
+		describeTaskRun(ctx, client, os.Args[2])
+	case "rerun":
+		rerunTaskRun(ctx, client, os.Args[2])
+	case "prune":
+		pruneTaskRuns(ctx, client, os.Args[2:])
+	}
+}
+
+// startTask creates a TaskRun for taskName.
+func startTask(ctx context.Context, client *miniclient.Clientset, taskName string, args []string) {
+
+	params, err := parseParams(args)
+	if err != nil {
+		fmt.Println("Invalid params:", err)
+		os.Exit(1)
+	}
+
 	taskRun := &miniv1.TaskRun{
 		ObjectMeta: metav1.ObjectMeta{
 			GenerateName: taskName + "-" + "run" + "-",
@@ -49,6 +94,7 @@ func main() {
 		},
 		Spec: miniv1.TaskRunSpec{
 			TaskRef: taskName,
+			Params:  params,
 		},
 	}
 
@@ -61,3 +107,282 @@ func main() {
 
 	fmt.Printf("TaskRun %v created successfully\n", createdTr.Name)
 }
+
+// cancelTaskRun asks the controller to stop a TaskRun by setting
+// spec.status to Cancelled. A run that already finished is left alone.
+func cancelTaskRun(ctx context.Context, client *miniclient.Clientset, trName string) {
+
+	tr, err := client.MinitaskV1().TaskRuns("default").Get(ctx, trName, metav1.GetOptions{})
+	if err != nil {
+		fmt.Println("Error getting TaskRun:", err)
+		os.Exit(1)
+	}
+
+	if resources.Finished(tr) {
+		fmt.Printf("TaskRun %v already finished: %s\n", trName, tr.Status.Phase)
+		return
+	}
+
+	patch := fmt.Sprintf(`{"spec":{"status":%q}}`, miniv1.TaskRunSpecStatusCancelled)
+
+	_, err = client.MinitaskV1().TaskRuns("default").Patch(ctx, trName, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+
+	if err != nil {
+		fmt.Println("Error cancelling TaskRun:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("TaskRun %v cancelled\n", trName)
+}
+
+// describeTaskRun prints the status of a TaskRun and the spec snapshot it
+// ran with.
+func describeTaskRun(ctx context.Context, client *miniclient.Clientset, trName string) {
+
+	tr, err := client.MinitaskV1().TaskRuns("default").Get(ctx, trName, metav1.GetOptions{})
+	if err != nil {
+		fmt.Println("Error getting TaskRun:", err)
+		os.Exit(1)
+	}
+
+	task := "(inline taskSpec)"
+	if tr.Spec.TaskRef != "" {
+		task = tr.Spec.TaskRef
+		if tr.Spec.Kind != "" {
+			task += " (" + tr.Spec.Kind + ")"
+		}
+	}
+
+	fmt.Println("Name:     ", tr.Name)
+	fmt.Println("Task:     ", task)
+	fmt.Println("Phase:    ", tr.Status.Phase)
+
+	if cond := meta.FindStatusCondition(tr.Status.Conditions, miniv1.TaskRunConditionSucceeded); cond != nil {
+		fmt.Printf("Reason:    %s: %s\n", cond.Reason, cond.Message)
+	}
+	if tr.Status.PodName != "" {
+		fmt.Println("Pod:      ", tr.Status.PodName)
+	}
+	if tr.Status.StartTime != nil {
+		fmt.Println("Started:  ", tr.Status.StartTime.Format(time.RFC3339))
+	}
+	if tr.Status.FinishTime != nil {
+		fmt.Println("Finished: ", tr.Status.FinishTime.Format(time.RFC3339))
+	}
+
+	if len(tr.Status.Steps) > 0 {
+		fmt.Println("Steps:")
+		for _, step := range tr.Status.Steps {
+			line := fmt.Sprintf("  %s\t%s", step.Name, step.State)
+			if step.ExitCode != nil {
+				line += fmt.Sprintf("\texit %d", *step.ExitCode)
+			}
+			if step.Reason != "" {
+				line += "\t" + step.Reason
+			}
+			if step.LogLocation != "" {
+				line += "\tlog " + step.LogLocation
+			}
+			fmt.Println(line)
+		}
+	}
+
+	if len(tr.Status.Results) > 0 {
+		fmt.Println("Results:")
+		for _, result := range tr.Status.Results {
+			fmt.Printf("  %s: %s\n", result.Name, result.Value)
+		}
+	}
+
+	if tr.Status.TaskSpec == nil {
+		fmt.Println("Spec:      not recorded, the run has not started")
+		return
+	}
+
+	// the snapshot, not the current Task, is what the run executed
+	out, err := yaml.Marshal(tr.Status.TaskSpec)
+	if err != nil {
+		fmt.Println("Error printing spec:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Spec:")
+	for _, line := range strings.Split(strings.TrimRight(string(out), "\n"), "\n") {
+		fmt.Println("  " + line)
+	}
+}
+
+// rerunTaskRun starts a new TaskRun with the spec snapshot of an earlier
+// one, so it runs the same steps even if the Task changed since.
+func rerunTaskRun(ctx context.Context, client *miniclient.Clientset, trName string) {
+
+	tr, err := client.MinitaskV1().TaskRuns("default").Get(ctx, trName, metav1.GetOptions{})
+	if err != nil {
+		fmt.Println("Error getting TaskRun:", err)
+		os.Exit(1)
+	}
+
+	if tr.Status.TaskSpec == nil {
+		fmt.Printf("TaskRun %v has no recorded spec, it never started\n", trName)
+		os.Exit(1)
+	}
+
+	spec := *tr.Spec.DeepCopy()
+	spec.TaskRef = ""
+	spec.Kind = ""
+	spec.TaskSpec = tr.Status.TaskSpec.DeepCopy()
+	spec.Status = ""
+
+	generateName := tr.GenerateName
+	if generateName == "" {
+		generateName = tr.Name + "-"
+	}
+
+	taskRun := &miniv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: generateName,
+			Namespace:    "default",
+		},
+		Spec: spec,
+	}
+
+	createdTr, err := client.MinitaskV1().TaskRuns("default").Create(ctx, taskRun, metav1.CreateOptions{})
+	if err != nil {
+		fmt.Println("Error creating TaskRun:", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("TaskRun %v created successfully\n", createdTr.Name)
+}
+
+// pruneTaskRuns deletes the finished TaskRuns the controller would delete:
+// those past their ttlSecondsAfterFinished and those beyond the history
+// limits of their Task. With --dry-run it only lists them.
+func pruneTaskRuns(ctx context.Context, client *miniclient.Clientset, args []string) {
+
+	dryRun := false
+	for _, arg := range args {
+		if arg != "--dry-run" {
+			fmt.Printf("Unexpected argument %q\n", arg)
+			fmt.Println(usage)
+			os.Exit(1)
+		}
+		dryRun = true
+	}
+
+	list, err := client.MinitaskV1().TaskRuns("default").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		fmt.Println("Error listing TaskRuns:", err)
+		os.Exit(1)
+	}
+
+	var runs []*miniv1.TaskRun
+	for i := range list.Items {
+		runs = append(runs, &list.Items[i])
+	}
+
+	reasons := map[string]string{}
+	var prune []string
+
+	mark := func(tr *miniv1.TaskRun, reason string) {
+		if _, ok := reasons[tr.Name]; ok {
+			return
+		}
+		reasons[tr.Name] = reason
+		prune = append(prune, tr.Name)
+	}
+
+	now := time.Now()
+	for _, tr := range runs {
+		if remaining, ok := resources.TTLRemaining(tr, now); ok && remaining <= 0 {
+			mark(tr, "ttl expired")
+		}
+	}
+
+	// history limits, once per referenced Task
+	done := map[string]bool{}
+	for _, tr := range runs {
+		if tr.Spec.TaskRef == "" || done[tr.Spec.Kind+"/"+tr.Spec.TaskRef] {
+			continue
+		}
+		done[tr.Spec.Kind+"/"+tr.Spec.TaskRef] = true
+
+		spec, err := resources.ResolveTaskSpec(tr,
+			func(name string) (*miniv1.Task, error) {
+				return client.MinitaskV1().Tasks("default").Get(ctx, name, metav1.GetOptions{})
+			},
+			func(name string) (*miniv1.ClusterTask, error) {
+				return client.MinitaskV1().ClusterTasks().Get(ctx, name, metav1.GetOptions{})
+			},
+		)
+		if err != nil {
+			continue
+		}
+
+		var sameTask []*miniv1.TaskRun
+		for _, run := range runs {
+			if resources.SameTask(tr, run) {
+				sameTask = append(sameTask, run)
+			}
+		}
+
+		for _, old := range resources.OverHistoryLimit(spec, sameTask) {
+			mark(old, "over history limit of "+tr.Spec.TaskRef)
+		}
+	}
+
+	if len(prune) == 0 {
+		fmt.Println("Nothing to prune")
+		return
+	}
+
+	policy := metav1.DeletePropagationBackground
+
+	for _, name := range prune {
+		if dryRun {
+			fmt.Printf("TaskRun %v would be deleted (%s)\n", name, reasons[name])
+			continue
+		}
+
+		err := client.MinitaskV1().TaskRuns("default").Delete(ctx, name, metav1.DeleteOptions{PropagationPolicy: &policy})
+		if err != nil && !apierrors.IsNotFound(err) {
+			fmt.Println("Error deleting TaskRun:", err)
+			os.Exit(1)
+		}
+
+		fmt.Printf("TaskRun %v deleted (%s)\n", name, reasons[name])
+	}
+}
+
+// parseParams turns "-p name=value" pairs into TaskRun params.
+// Values starting with [ or { are read as JSON arrays and objects.
+func parseParams(args []string) ([]miniv1.Param, error) {
+
+	var params []miniv1.Param
+
+	for i := 0; i < len(args); i++ {
+		if args[i] != "-p" || i+1 >= len(args) {
+			return nil, fmt.Errorf("unexpected argument %q", args[i])
+		}
+		i++
+
+		name, value, ok := strings.Cut(args[i], "=")
+		if !ok || name == "" {
+			return nil, fmt.Errorf("expected name=value, got %q", args[i])
+		}
+
+		param := miniv1.Param{Name: name}
+
+		if strings.HasPrefix(value, "[") || strings.HasPrefix(value, "{") {
+			if err := json.Unmarshal([]byte(value), &param.Value); err != nil {
+				return nil, fmt.Errorf("param %q: %v", name, err)
+			}
+		} else {
+			param.Value = miniv1.ParamValue{Type: miniv1.ParamTypeString, StringVal: value}
+		}
+
+		params = append(params, param)
+	}
+
+	return params, nil
+}
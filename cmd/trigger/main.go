@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+
+	miniclient "github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	"github.com/ankrsinha/mini-task/pkg/trigger"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// maxPayloadSize bounds the request bodies the server reads
+const maxPayloadSize = 1 << 20
+
+// Server creates TaskRuns from webhook requests sent to a Trigger.
+type Server struct {
+	ctx context.Context
+
+	kubeClient *kubernetes.Clientset
+	miniClient *miniclient.Clientset
+}
+
+func main() {
+
+	addr := flag.String("addr", ":8080", "address to listen on")
+	flag.Parse()
+
+	// Provides shared execution context.
+	ctx := context.Background()
+
+	kubeconfig := clientcmd.RecommendedHomeFile
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		fmt.Println("Error building kubeconfig:", err)
+		os.Exit(1)
+	}
+
+	// create kubernetes client
+	kubeClient, err := kubernetes.NewForConfig(config)
+	if err != nil {
+		fmt.Println("Error creating kube client:", err)
+		os.Exit(1)
+	}
+
+	// create generated client
+	miniClient, err := miniclient.NewForConfig(config)
+	if err != nil {
+		fmt.Println("Error creating mini client:", err)
+		os.Exit(1)
+	}
+
+	server := &Server{
+		ctx:        ctx,
+		kubeClient: kubeClient,
+		miniClient: miniClient,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /triggers/{namespace}/{name}", server.handleTrigger)
+
+	fmt.Println("Trigger server listening on", *addr)
+
+	if err := http.ListenAndServe(*addr, mux); err != nil {
+		fmt.Println("Error serving:", err)
+		os.Exit(1)
+	}
+}
+
+// handleTrigger verifies the request against the Trigger, creates the
+// TaskRun and answers with its name.
+func (s *Server) handleTrigger(w http.ResponseWriter, r *http.Request) {
+
+	namespace := r.PathValue("namespace")
+	name := r.PathValue("name")
+
+	fmt.Println("--------------------------------------------------")
+	fmt.Println("Trigger request:", namespace+"/"+name)
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxPayloadSize))
+	if err != nil {
+		reply(w, http.StatusRequestEntityTooLarge, "error", "payload too large")
+		return
+	}
+
+	t, err := s.miniClient.MinitaskV1().Triggers(namespace).Get(r.Context(), name, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			reply(w, http.StatusNotFound, "error", "trigger not found")
+			return
+		}
+		fmt.Println("Error getting Trigger:", err)
+		reply(w, http.StatusInternalServerError, "error", "cannot read trigger")
+		return
+	}
+
+	// fail closed: a Trigger without a secret would let anyone who can
+	// reach the server create TaskRuns in its namespace
+	if t.Spec.Secret == nil {
+		fmt.Println("Trigger has no secret:", namespace+"/"+name)
+		reply(w, http.StatusInternalServerError, "error", "trigger has no secret")
+		return
+	}
+
+	secret, err := s.kubeClient.CoreV1().Secrets(namespace).Get(r.Context(), t.Spec.Secret.Name, metav1.GetOptions{})
+	if err != nil {
+		fmt.Println("Error getting Secret:", err)
+		reply(w, http.StatusInternalServerError, "error", "cannot read trigger secret")
+		return
+	}
+
+	// an empty key is as good as none
+	key := secret.Data[t.Spec.Secret.Key]
+	if len(key) == 0 {
+		fmt.Println("Secret has no key:", t.Spec.Secret.Key)
+		reply(w, http.StatusInternalServerError, "error", "cannot read trigger secret")
+		return
+	}
+
+	if err := trigger.VerifySignature(body, key, r.Header.Get(trigger.SignatureHeader)); err != nil {
+		fmt.Println("Rejected request:", err)
+		reply(w, http.StatusUnauthorized, "error", err.Error())
+		return
+	}
+
+	tr, err := trigger.MakeTaskRun(t, body)
+	if err != nil {
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			reply(w, http.StatusBadRequest, "error", verr.Message)
+			return
+		}
+		reply(w, http.StatusInternalServerError, "error", err.Error())
+		return
+	}
+
+	created, err := s.miniClient.MinitaskV1().TaskRuns(namespace).Create(s.ctx, tr, metav1.CreateOptions{})
+	if err != nil {
+		fmt.Println("Error creating TaskRun:", err)
+		if apierrors.IsInvalid(err) {
+			reply(w, http.StatusBadRequest, "error", err.Error())
+			return
+		}
+		reply(w, http.StatusInternalServerError, "error", "cannot create taskrun")
+		return
+	}
+
+	fmt.Println("Created TaskRun:", created.Name)
+
+	reply(w, http.StatusCreated, "taskRun", created.Name)
+}
+
+// reply writes a one-field JSON object such as {"taskRun": "<name>"}
+func reply(w http.ResponseWriter, status int, key, value string) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(map[string]string{key: value})
+}
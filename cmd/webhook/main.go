@@ -0,0 +1,29 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+
+	"github.com/ankrsinha/mini-task/pkg/webhook"
+)
+
+func main() {
+
+	addr := flag.String("addr", ":8443", "address to listen on")
+	certFile := flag.String("tls-cert-file", "/etc/webhook/certs/tls.crt", "TLS certificate the API server trusts")
+	keyFile := flag.String("tls-private-key-file", "/etc/webhook/certs/tls.key", "private key of the TLS certificate")
+	flag.Parse()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /validate", webhook.ServeValidate)
+
+	fmt.Println("Webhook server listening on", *addr)
+
+	// the API server only calls webhooks over HTTPS
+	if err := http.ListenAndServeTLS(*addr, *certFile, *keyFile, mux); err != nil {
+		fmt.Println("Error serving:", err)
+		os.Exit(1)
+	}
+}
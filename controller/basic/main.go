@@ -2,13 +2,16 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"os"
 	"time"
 
 	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
 	miniclient "github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned"
+	"github.com/ankrsinha/mini-task/pkg/resources"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/client-go/kubernetes"
@@ -79,8 +82,9 @@ func main() {
 			case "Pending", "Running":
 				handleActiveTaskRun(ctx, miniClient, coreClient, &tr)
 
-			case "Succeeded", "Failed":
-				fmt.Println("TaskRun already completed. Skipping.")
+			case "Succeeded", "Failed", "Cancelled":
+				fmt.Println("TaskRun already completed.")
+				handleFinishedTaskRun(ctx, miniClient, &tr)
 
 			default:
 				fmt.Println("Unknown Phase:", tr.Status.Phase)
@@ -93,11 +97,29 @@ func main() {
 	}
 }
 
+// handleFinishedTaskRun deletes tr once its ttlSecondsAfterFinished has
+// passed; its Pod is garbage collected with it.
+func handleFinishedTaskRun(ctx context.Context, miniClient *miniclient.Clientset, tr *miniv1.TaskRun) {
+
+	remaining, ok := resources.TTLRemaining(tr, time.Now())
+	if !ok || remaining > 0 {
+		return
+	}
+
+	fmt.Println("TaskRun TTL expired. Deleting:", tr.Name)
+
+	policy := metav1.DeletePropagationBackground
+	err := miniClient.MinitaskV1().TaskRuns(tr.Namespace).Delete(ctx, tr.Name, metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil && !apierrors.IsNotFound(err) {
+		fmt.Println("Error deleting TaskRun:", err)
+	}
+}
+
 func handleNewTaskRun(ctx context.Context, miniClient *miniclient.Clientset, coreClient *kubernetes.Clientset, tr *miniv1.TaskRun) {
 
 	// create pod
 
-	podName := tr.Name + "-pod"
+	podName := resources.PodName(tr)
 
 	// Check if pod already exists
 	fmt.Println("Checking if Pod exists...")
@@ -117,41 +139,92 @@ func handleNewTaskRun(ctx context.Context, miniClient *miniclient.Clientset, cor
 
 	fmt.Println("Creating Pod:", podName)
 
-	task, err := miniClient.
-		MinitaskV1().
-		Tasks("default").
-		Get(ctx, tr.Spec.TaskRef, metav1.GetOptions{})
+	// the checks of the admission webhook, for runs created without it
+	err = resources.ValidateTaskRun(&tr.Spec)
 
-	if err != nil {
+	// inline taskSpec or referenced Task/ClusterTask
+	var taskSpec *miniv1.TaskSpec
+	if err == nil {
+		taskSpec, err = resources.ResolveTaskSpec(tr,
+			func(name string) (*miniv1.Task, error) {
+				return miniClient.
+					MinitaskV1().
+					Tasks("default").
+					Get(ctx, name, metav1.GetOptions{})
+			},
+			func(name string) (*miniv1.ClusterTask, error) {
+				return miniClient.
+					MinitaskV1().
+					ClusterTasks().
+					Get(ctx, name, metav1.GetOptions{})
+			},
+		)
+	}
+
+	if err != nil && !errors.As(err, new(*resources.ValidationError)) {
 		fmt.Println("Error fetching Task:", err)
 		return
 	}
 
-	containers := []corev1.Container{}
+	// substitute $(params.*) and reject invalid runs: an invalid spec or
+	// task source, missing or invalid params, an invalid task or unbound
+	// workspaces
+	var spec *miniv1.TaskSpec
+	if err == nil {
+		spec, err = resources.ApplyParams(taskSpec, tr.Spec.Params)
+	}
+	if err == nil {
+		err = resources.ValidateTask(spec)
+	}
+	if err == nil {
+		err = resources.ValidateWorkspaces(spec, tr.Spec.Workspaces)
+	}
+	if err != nil {
+		fmt.Println("Invalid TaskRun:", err)
+
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			trCopy := tr.DeepCopy()
+			resources.SetPhase(trCopy, "Failed", verr.Reason, verr.Message)
+			now := metav1.Now()
+			trCopy.Status.FinishTime = &now
+
+			miniClient.
+				MinitaskV1().
+				TaskRuns("default").
+				UpdateStatus(ctx, trCopy, metav1.UpdateOptions{})
+		}
+		return
+	}
+
+	// volumeClaimTemplate workspaces need their PVC before the Pod
+	for _, pvc := range resources.MakeWorkspacePVCs(tr) {
+		_, err := coreClient.CoreV1().
+			PersistentVolumeClaims("default").
+			Create(ctx, pvc, metav1.CreateOptions{})
 
-	for _, step := range task.Spec.Steps {
-		container := corev1.Container{
-			Name:    step.Name,
-			Image:   step.Image,
-			Command: []string{"/bin/sh", "-c"},
-			Args:    []string{step.Script},
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			fmt.Println("Error creating PVC:", err)
+			return
 		}
-		containers = append(containers, container)
 	}
 
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podName,
-			Namespace: "default",
-			Labels: map[string]string{
-				"minitask": tr.Name,
-			},
-		},
-		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyNever,
-			Containers:    containers,
-		},
+	// namespace-wide podTemplate, overridden by the TaskRun's own
+	var defaults *miniv1.PodTemplate
+	cm, err := coreClient.CoreV1().
+		ConfigMaps("default").
+		Get(ctx, resources.DefaultsConfigMapName, metav1.GetOptions{})
+
+	if err == nil {
+		defaults, err = resources.ParsePodTemplate(cm)
 	}
+	if err != nil && !apierrors.IsNotFound(err) {
+		fmt.Println("Error reading default podTemplate:", err)
+		return
+	}
+
+	// steps run in declared order, see resources.MakePod
+	pod := resources.MakePod(tr, spec, defaults)
 
 	_, err = coreClient.CoreV1().
 		Pods("default").
@@ -159,6 +232,19 @@ func handleNewTaskRun(ctx context.Context, miniClient *miniclient.Clientset, cor
 
 	if err != nil {
 		fmt.Println("Error creating Pod:", err)
+
+		// the API server rejected the Pod spec; retrying cannot fix it
+		if apierrors.IsInvalid(err) {
+			trCopy := tr.DeepCopy()
+			resources.SetPhase(trCopy, "Failed", "PodCreationFailed", err.Error())
+			now := metav1.Now()
+			trCopy.Status.FinishTime = &now
+
+			miniClient.
+				MinitaskV1().
+				TaskRuns("default").
+				UpdateStatus(ctx, trCopy, metav1.UpdateOptions{})
+		}
 		return
 	}
 
@@ -167,8 +253,9 @@ func handleNewTaskRun(ctx context.Context, miniClient *miniclient.Clientset, cor
 	// update status -> pending
 
 	trCopy := tr.DeepCopy()
-	trCopy.Status.Phase = "Pending"
+	resources.SetPhase(trCopy, "Pending", "PodCreated", "Pod "+podName+" created")
 	trCopy.Status.PodName = podName
+	trCopy.Status.TaskSpec = spec.DeepCopy()
 
 	_, err = miniClient.
 		MinitaskV1().
@@ -200,7 +287,7 @@ func handleActiveTaskRun(ctx context.Context, miniClient *miniclient.Clientset,
 			fmt.Println("Pod missing. Marking TaskRun as Failed.")
 
 			trCopy := tr.DeepCopy()
-			trCopy.Status.Phase = "Failed"
+			resources.SetPhase(trCopy, "Failed", "PodMissing", "Pod "+podName+" no longer exists")
 			now := metav1.Now()
 			trCopy.Status.FinishTime = &now
 
@@ -220,16 +307,24 @@ func handleActiveTaskRun(ctx context.Context, miniClient *miniclient.Clientset,
 
 	oldPhase := tr.Status.Phase
 	newPhase := oldPhase
+	var reason, message string
 
 	trCopy := tr.DeepCopy()
 
 	switch pod.Status.Phase {
 
 	case corev1.PodPending:
-		newPhase = "Pending"
+		// the pod stays Pending while the init container steps run
+		if !resources.StepsStarted(pod) {
+			newPhase = "Pending"
+			message = "Waiting for Pod to start"
+			break
+		}
+		fallthrough
 
 	case corev1.PodRunning:
 		newPhase = "Running"
+		message = "Steps are running"
 		if trCopy.Status.StartTime == nil {
 			now := metav1.Now()
 			trCopy.Status.StartTime = &now
@@ -238,20 +333,34 @@ func handleActiveTaskRun(ctx context.Context, miniClient *miniclient.Clientset,
 
 	case corev1.PodSucceeded:
 		newPhase = "Succeeded"
+		message = "All steps completed successfully"
 		now := metav1.Now()
 		trCopy.Status.FinishTime = &now
+
+		// a declared result that was not written fails the run
+		results, err := resources.ParseResults(pod)
+		trCopy.Status.Results = results
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			newPhase = "Failed"
+			reason, message = verr.Reason, verr.Message
+		}
 		fmt.Println("Finished Successfully!!")
 
 	case corev1.PodFailed:
 		newPhase = "Failed"
+		reason, message = resources.PodFailure(pod)
 		now := metav1.Now()
 		trCopy.Status.FinishTime = &now
 		fmt.Println("Failed!!")
 	}
 
-	if oldPhase != newPhase {
+	trCopy.Status.Steps = resources.StepStates(pod)
+	stepsChanged := !equality.Semantic.DeepEqual(tr.Status.Steps, trCopy.Status.Steps)
+
+	if oldPhase != newPhase || stepsChanged || tr.Status.ObservedGeneration != tr.Generation {
 		fmt.Printf("Phase Transition %s -> %s\n", oldPhase, newPhase)
-		trCopy.Status.Phase = newPhase
+		resources.SetPhase(trCopy, newPhase, reason, message)
 
 		_, err = miniClient.
 			MinitaskV1().
@@ -0,0 +1,418 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	// timeZone must resolve in images without a zoneinfo database
+	_ "time/tzdata"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	miniclient "github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned"
+	miniInformers "github.com/ankrsinha/mini-task/pkg/generated/informers/externalversions"
+	minilisterv1 "github.com/ankrsinha/mini-task/pkg/generated/listers/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	"github.com/ankrsinha/mini-task/pkg/schedule"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/utils/clock"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Controller creates TaskRuns for CronTaskRuns on schedule. The TaskRuns
+// themselves are run by the TaskRun controller.
+type Controller struct {
+	ctx context.Context
+
+	miniClient miniclient.Interface
+
+	ctrInformer cache.SharedIndexInformer
+	trInformer  cache.SharedIndexInformer
+
+	ctrLister minilisterv1.CronTaskRunLister
+	trLister  minilisterv1.TaskRunLister
+
+	queue workqueue.TypedRateLimitingInterface[cache.ObjectName]
+
+	// clock is the source of "now", swapped for a fake clock in tests
+	clock clock.PassiveClock
+}
+
+func main() {
+	// Provides shared execution context.
+	ctx := context.Background()
+
+	kubeconfig := clientcmd.RecommendedHomeFile
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		fmt.Println("Error building kubeconfig:", err)
+		os.Exit(1)
+	}
+
+	// create generated client
+	miniClient, err := miniclient.NewForConfig(config)
+	if err != nil {
+		fmt.Println("Error creating mini client:", err)
+		os.Exit(1)
+	}
+
+	miniFactory := miniInformers.NewSharedInformerFactory(miniClient, 0)
+
+	controller := NewController(ctx, miniClient, miniFactory, clock.RealClock{})
+
+	// start informers
+	stopCh := make(chan struct{}) // channel used to stop exec (graceful shutdown)
+	defer close(stopCh)
+
+	go controller.ctrInformer.Run(stopCh)
+	go controller.trInformer.Run(stopCh)
+
+	// wait for initial cache sync
+	if !cache.WaitForCacheSync(stopCh, controller.ctrInformer.HasSynced, controller.trInformer.HasSynced) {
+		fmt.Println("Failed to sync caches")
+		os.Exit(1)
+	}
+
+	// start worker
+	go controller.runWorker()
+
+	// block forever
+	select {}
+}
+
+// NewController wires the informers and event handlers. clk decides what
+// "now" is for every schedule decision.
+func NewController(ctx context.Context, miniClient miniclient.Interface, miniFactory miniInformers.SharedInformerFactory, clk clock.PassiveClock) *Controller {
+
+	controller := &Controller{
+		ctx:         ctx,
+		miniClient:  miniClient,
+		ctrInformer: miniFactory.Minitask().V1().CronTaskRuns().Informer(),
+		trInformer:  miniFactory.Minitask().V1().TaskRuns().Informer(),
+		ctrLister:   miniFactory.Minitask().V1().CronTaskRuns().Lister(),
+		trLister:    miniFactory.Minitask().V1().TaskRuns().Lister(),
+		queue: workqueue.NewTypedRateLimitingQueue(
+			workqueue.DefaultTypedControllerRateLimiter[cache.ObjectName](),
+		),
+		clock: clk,
+	}
+
+	// attaching event handlers to the informers
+
+	controller.ctrInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueueCronTaskRun,
+		UpdateFunc: func(old, new interface{}) {
+			oldCtr := old.(*miniv1.CronTaskRun)
+			newCtr := new.(*miniv1.CronTaskRun)
+
+			if oldCtr.Generation == newCtr.Generation {
+				return
+			}
+
+			controller.enqueueCronTaskRun(new)
+		},
+	})
+
+	// scheduled TaskRuns wake up their CronTaskRun when their phase changes,
+	// so active runs and history stay current
+	controller.trInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleTaskRun,
+		UpdateFunc: func(old, new interface{}) {
+			oldTr := old.(*miniv1.TaskRun)
+			newTr := new.(*miniv1.TaskRun)
+
+			if oldTr.Status.Phase == newTr.Status.Phase {
+				return
+			}
+
+			controller.handleTaskRun(new)
+		},
+		DeleteFunc: controller.handleTaskRun,
+	})
+
+	return controller
+}
+
+func (c *Controller) enqueueCronTaskRun(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	c.queue.Add(cache.ObjectName{Namespace: namespace, Name: name})
+}
+
+func (c *Controller) handleTaskRun(obj interface{}) {
+
+	tr, ok := obj.(*miniv1.TaskRun)
+	if !ok {
+		return
+	}
+
+	ctrName := tr.Labels[schedule.CronTaskRunLabel]
+	if ctrName == "" {
+		return
+	}
+
+	c.queue.Add(cache.ObjectName{Namespace: tr.Namespace, Name: ctrName})
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer c.queue.Done(key)
+
+	requeueAfter, err := c.reconcile(key)
+	if err != nil {
+		fmt.Println("Error reconciling:", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+
+	// wake up again for the next schedule time
+	if requeueAfter > 0 {
+		c.queue.AddAfter(key, requeueAfter)
+	}
+
+	return true
+}
+
+// reconcile starts the run that is due, if any, and returns how long to wait
+// for the next schedule time.
+func (c *Controller) reconcile(key cache.ObjectName) (time.Duration, error) {
+
+	namespace := key.Namespace
+	name := key.Name
+
+	fmt.Println("--------------------------------------------------")
+	fmt.Println("Reconciling CronTaskRun:", name)
+
+	ctr, err := c.ctrLister.CronTaskRuns(namespace).Get(name)
+	if err != nil {
+		fmt.Println("CronTaskRun not found")
+		return 0, nil
+	}
+
+	children, err := c.trLister.TaskRuns(namespace).List(
+		labels.SelectorFromSet(labels.Set{schedule.CronTaskRunLabel: ctr.Name}),
+	)
+	if err != nil {
+		return 0, err
+	}
+
+	ctrCopy := ctr.DeepCopy()
+
+	active, err := c.syncHistory(ctrCopy, children)
+	if err != nil {
+		return 0, err
+	}
+
+	sched, err := schedule.Parse(ctr)
+	if err != nil {
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			// nothing to retry until the spec changes
+			fmt.Println("Invalid CronTaskRun:", verr.Message)
+			return 0, c.updateStatus(ctr, ctrCopy)
+		}
+		return 0, err
+	}
+
+	now := c.clock.Now()
+	requeueAfter := sched.Next(now).Sub(now)
+
+	scheduled, missed := schedule.MostRecent(ctrCopy, sched, now)
+	if scheduled == nil {
+		fmt.Println("No run due, next in", requeueAfter)
+		return requeueAfter, c.updateStatus(ctr, ctrCopy)
+	}
+
+	if missed > 1 {
+		fmt.Printf("Missed %d schedule times, starting only the latest\n", missed-1)
+	}
+
+	if schedule.Expired(ctrCopy, *scheduled, now) {
+		fmt.Println("Starting deadline passed for", scheduled.Format(time.RFC3339), "Skipping.")
+		return requeueAfter, c.updateStatus(ctr, ctrCopy)
+	}
+
+	switch ctr.Spec.ConcurrencyPolicy {
+
+	case miniv1.ForbidConcurrent:
+		if len(active) > 0 {
+			// lastScheduleTime stays put, so the run starts once the
+			// active ones finish, unless the starting deadline passes first
+			fmt.Println("Run still active, concurrency forbidden. Skipping.")
+			return requeueAfter, c.updateStatus(ctr, ctrCopy)
+		}
+
+	case miniv1.ReplaceConcurrent:
+		for _, tr := range active {
+			if err := c.cancelTaskRun(tr); err != nil {
+				return 0, err
+			}
+		}
+	}
+
+	tr := schedule.MakeTaskRun(ctr, *scheduled)
+
+	fmt.Println("Creating TaskRun:", tr.Name)
+
+	created, err := c.miniClient.MinitaskV1().TaskRuns(namespace).Create(c.ctx, tr, metav1.CreateOptions{})
+	if err != nil {
+		if !apierrors.IsAlreadyExists(err) {
+			return 0, err
+		}
+		fmt.Println("TaskRun already exists (race). Skipping.")
+		created = tr
+	}
+
+	if !hasActive(ctrCopy, created.Name) {
+		ctrCopy.Status.Active = append(ctrCopy.Status.Active, activeRef(created))
+	}
+	ctrCopy.Status.LastScheduleTime = &metav1.Time{Time: *scheduled}
+
+	return requeueAfter, c.updateStatus(ctr, ctrCopy)
+}
+
+// syncHistory rebuilds the active list and lastSuccessfulTime from the
+// scheduled TaskRuns and deletes finished runs beyond the history limits. It
+// returns the active runs.
+func (c *Controller) syncHistory(ctr *miniv1.CronTaskRun, children []*miniv1.TaskRun) ([]*miniv1.TaskRun, error) {
+
+	var active, succeeded, failed []*miniv1.TaskRun
+
+	for _, tr := range children {
+		switch tr.Status.Phase {
+		case "Succeeded":
+			succeeded = append(succeeded, tr)
+		case "Failed", "Cancelled":
+			failed = append(failed, tr)
+		default:
+			active = append(active, tr)
+		}
+	}
+
+	ctr.Status.Active = nil
+	for _, tr := range active {
+		ctr.Status.Active = append(ctr.Status.Active, activeRef(tr))
+	}
+
+	for _, tr := range succeeded {
+		if tr.Status.FinishTime == nil {
+			continue
+		}
+		if ctr.Status.LastSuccessfulTime == nil || tr.Status.FinishTime.After(ctr.Status.LastSuccessfulTime.Time) {
+			finished := *tr.Status.FinishTime
+			ctr.Status.LastSuccessfulTime = &finished
+		}
+	}
+
+	successfulLimit, failedLimit := schedule.HistoryLimits(ctr)
+
+	if err := c.deleteOldest(succeeded, successfulLimit); err != nil {
+		return nil, err
+	}
+	if err := c.deleteOldest(failed, failedLimit); err != nil {
+		return nil, err
+	}
+
+	return active, nil
+}
+
+// deleteOldest deletes all but the newest limit runs.
+func (c *Controller) deleteOldest(runs []*miniv1.TaskRun, limit int) error {
+
+	if len(runs) <= limit {
+		return nil
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return runs[i].CreationTimestamp.Before(&runs[j].CreationTimestamp)
+	})
+
+	for _, tr := range runs[:len(runs)-limit] {
+
+		fmt.Println("Deleting old TaskRun:", tr.Name)
+
+		err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).Delete(c.ctx, tr.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// cancelTaskRun asks the TaskRun controller to stop an active run.
+func (c *Controller) cancelTaskRun(tr *miniv1.TaskRun) error {
+
+	fmt.Println("Replacing active TaskRun:", tr.Name)
+
+	patch := fmt.Sprintf(`{"spec":{"status":%q}}`, miniv1.TaskRunSpecStatusCancelled)
+
+	_, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).Patch(c.ctx, tr.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// updateStatus writes the status of ctrCopy if it differs from ctr.
+func (c *Controller) updateStatus(ctr, ctrCopy *miniv1.CronTaskRun) error {
+
+	if equality.Semantic.DeepEqual(ctr.Status, ctrCopy.Status) {
+		return nil
+	}
+
+	_, err := c.miniClient.MinitaskV1().CronTaskRuns(ctr.Namespace).UpdateStatus(c.ctx, ctrCopy, metav1.UpdateOptions{})
+
+	return err
+}
+
+func activeRef(tr *miniv1.TaskRun) corev1.ObjectReference {
+	return corev1.ObjectReference{
+		APIVersion: miniv1.SchemeGroupVersion.String(),
+		Kind:       "TaskRun",
+		Namespace:  tr.Namespace,
+		Name:       tr.Name,
+		UID:        tr.UID,
+	}
+}
+
+func hasActive(ctr *miniv1.CronTaskRun, name string) bool {
+	for _, ref := range ctr.Status.Active {
+		if ref.Name == name {
+			return true
+		}
+	}
+	return false
+}
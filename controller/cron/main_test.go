@@ -0,0 +1,211 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned/fake"
+	miniInformers "github.com/ankrsinha/mini-task/pkg/generated/informers/externalversions"
+	"github.com/ankrsinha/mini-task/pkg/schedule"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/cache"
+	clocktesting "k8s.io/utils/clock/testing"
+	"k8s.io/utils/ptr"
+)
+
+var created = time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+
+// newTestController returns a controller whose clock stands at now and
+// whose caches hold objects, as if the informers had synced them.
+func newTestController(t *testing.T, now time.Time, objects ...runtime.Object) (*Controller, *fake.Clientset) {
+
+	client := fake.NewSimpleClientset(objects...)
+	factory := miniInformers.NewSharedInformerFactory(client, 0)
+
+	c := NewController(context.Background(), client, factory, clocktesting.NewFakePassiveClock(now))
+	t.Cleanup(c.queue.ShutDown)
+
+	for _, obj := range objects {
+		var err error
+		switch obj.(type) {
+		case *miniv1.CronTaskRun:
+			err = c.ctrInformer.GetIndexer().Add(obj)
+		case *miniv1.TaskRun:
+			err = c.trInformer.GetIndexer().Add(obj)
+		}
+		if err != nil {
+			t.Fatalf("adding %T to cache: %v", obj, err)
+		}
+	}
+
+	return c, client
+}
+
+func cronTaskRun(policy miniv1.ConcurrencyPolicy, deadline *int64) *miniv1.CronTaskRun {
+	return &miniv1.CronTaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: miniv1.CronTaskRunSpec{
+			Schedule:                "*/5 * * * *",
+			ConcurrencyPolicy:       policy,
+			StartingDeadlineSeconds: deadline,
+			TaskRunTemplate: miniv1.TaskRunTemplateSpec{
+				Spec: miniv1.TaskRunSpec{TaskRef: "task-hello"},
+			},
+		},
+	}
+}
+
+// activeRun is a run of ctr scheduled at the first schedule time that has
+// not finished yet
+func activeRun(ctr *miniv1.CronTaskRun) *miniv1.TaskRun {
+	tr := schedule.MakeTaskRun(ctr, created.Add(5*time.Minute))
+	tr.CreationTimestamp = metav1.NewTime(created.Add(5 * time.Minute))
+	tr.Status.Phase = "Running"
+	return tr
+}
+
+func TestReconcile(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		policy   miniv1.ConcurrencyPolicy
+		deadline *int64
+		active   bool
+		now      time.Time
+
+		// the run expected to be created, nil for none
+		wantRun *time.Time
+		// whether the active run is expected to be cancelled
+		wantCancelled bool
+		wantRequeue   time.Duration
+	}{
+		{
+			name:        "nothing due",
+			now:         created.Add(4 * time.Minute),
+			wantRequeue: time.Minute,
+		},
+		{
+			name:        "missed runs start only the latest",
+			now:         created.Add(17 * time.Minute),
+			wantRun:     ptr.To(created.Add(15 * time.Minute)),
+			wantRequeue: 3 * time.Minute,
+		},
+		{
+			name:        "within the starting deadline",
+			deadline:    ptr.To[int64](180),
+			now:         created.Add(17 * time.Minute),
+			wantRun:     ptr.To(created.Add(15 * time.Minute)),
+			wantRequeue: 3 * time.Minute,
+		},
+		{
+			name:        "past the starting deadline",
+			deadline:    ptr.To[int64](60),
+			now:         created.Add(17 * time.Minute),
+			wantRequeue: 3 * time.Minute,
+		},
+		{
+			name:        "Allow starts next to the active run",
+			policy:      miniv1.AllowConcurrent,
+			active:      true,
+			now:         created.Add(10 * time.Minute),
+			wantRun:     ptr.To(created.Add(10 * time.Minute)),
+			wantRequeue: 5 * time.Minute,
+		},
+		{
+			name:        "Forbid waits for the active run",
+			policy:      miniv1.ForbidConcurrent,
+			active:      true,
+			now:         created.Add(10 * time.Minute),
+			wantRequeue: 5 * time.Minute,
+		},
+		{
+			name:        "Forbid starts when no run is active",
+			policy:      miniv1.ForbidConcurrent,
+			now:         created.Add(10 * time.Minute),
+			wantRun:     ptr.To(created.Add(10 * time.Minute)),
+			wantRequeue: 5 * time.Minute,
+		},
+		{
+			name:          "Replace cancels the active run",
+			policy:        miniv1.ReplaceConcurrent,
+			active:        true,
+			now:           created.Add(10 * time.Minute),
+			wantRun:       ptr.To(created.Add(10 * time.Minute)),
+			wantCancelled: true,
+			wantRequeue:   5 * time.Minute,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr := cronTaskRun(tt.policy, tt.deadline)
+			objects := []runtime.Object{ctr}
+
+			var active *miniv1.TaskRun
+			if tt.active {
+				active = activeRun(ctr)
+				ctr.Status.LastScheduleTime = &metav1.Time{Time: created.Add(5 * time.Minute)}
+				objects = append(objects, active)
+			}
+
+			c, client := newTestController(t, tt.now, objects...)
+
+			requeue, err := c.reconcile(cache.ObjectName{Namespace: ctr.Namespace, Name: ctr.Name})
+			if err != nil {
+				t.Fatalf("reconcile() error = %v", err)
+			}
+			if requeue != tt.wantRequeue {
+				t.Errorf("reconcile() requeue = %s, want %s", requeue, tt.wantRequeue)
+			}
+
+			runs, err := client.MinitaskV1().TaskRuns(ctr.Namespace).List(context.Background(), metav1.ListOptions{})
+			if err != nil {
+				t.Fatalf("listing TaskRuns: %v", err)
+			}
+
+			var started []string
+			for _, tr := range runs.Items {
+				if active == nil || tr.Name != active.Name {
+					started = append(started, tr.Name)
+				}
+			}
+
+			if tt.wantRun == nil {
+				if len(started) > 0 {
+					t.Errorf("created TaskRuns %v, want none", started)
+				}
+			} else {
+				want := schedule.TaskRunName(ctr, *tt.wantRun)
+				if len(started) != 1 || started[0] != want {
+					t.Errorf("created TaskRuns %v, want [%s]", started, want)
+				}
+
+				got, err := client.MinitaskV1().CronTaskRuns(ctr.Namespace).Get(context.Background(), ctr.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("getting CronTaskRun: %v", err)
+				}
+				if got.Status.LastScheduleTime == nil || !got.Status.LastScheduleTime.Time.Equal(*tt.wantRun) {
+					t.Errorf("lastScheduleTime = %v, want %s", got.Status.LastScheduleTime, tt.wantRun)
+				}
+			}
+
+			if active != nil {
+				got, err := client.MinitaskV1().TaskRuns(ctr.Namespace).Get(context.Background(), active.Name, metav1.GetOptions{})
+				if err != nil {
+					t.Fatalf("getting active TaskRun: %v", err)
+				}
+				cancelled := got.Spec.Status == miniv1.TaskRunSpecStatusCancelled
+				if cancelled != tt.wantCancelled {
+					t.Errorf("active TaskRun cancelled = %v, want %v", cancelled, tt.wantCancelled)
+				}
+			}
+		})
+	}
+}
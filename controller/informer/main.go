@@ -2,46 +2,108 @@ package main
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"os"
+	"os/signal"
+	"slices"
+	"syscall"
+	"time"
 
 	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
 	miniclient "github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned"
+	minischeme "github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned/scheme"
 	miniInformers "github.com/ankrsinha/mini-task/pkg/generated/informers/externalversions"
 	minilisterv1 "github.com/ankrsinha/mini-task/pkg/generated/listers/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/logs"
+	"github.com/ankrsinha/mini-task/pkg/resources"
 	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/apimachinery/pkg/util/uuid"
 	corelistersv1 "k8s.io/client-go/listers/core/v1"
 
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/tools/cache"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/leaderelection"
+	"k8s.io/client-go/tools/leaderelection/resourcelock"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/client-go/util/workqueue"
 
+	"k8s.io/apimachinery/pkg/api/equality"
 	apierrors "k8s.io/apimachinery/pkg/api/errors"
 )
 
 type Controller struct {
+	// set by run: cancelled on shutdown or when leadership is lost, so a
+	// replica that lost the Lease stops writing at once
 	ctx context.Context
 
 	miniClient *miniclient.Clientset
 	coreClient *kubernetes.Clientset
 
-	trInformer   cache.SharedIndexInformer
-	podInformer  cache.SharedIndexInformer
-	taskInformer cache.SharedIndexInformer
+	trInformer          cache.SharedIndexInformer
+	podInformer         cache.SharedIndexInformer
+	taskInformer        cache.SharedIndexInformer
+	clusterTaskInformer cache.SharedIndexInformer
 
-	trLister   minilisterv1.TaskRunLister
-	podLister  corelistersv1.PodLister
-	taskLister minilisterv1.TaskLister
+	trLister          minilisterv1.TaskRunLister
+	podLister         corelistersv1.PodLister
+	taskLister        minilisterv1.TaskLister
+	clusterTaskLister minilisterv1.ClusterTaskLister
 
 	queue workqueue.TypedRateLimitingInterface[cache.ObjectName]
+
+	// used when neither the TaskRun nor its Task set a timeout
+	defaultTimeout time.Duration
+
+	// how long a TaskRun waits for its missing Task before it fails
+	taskNotFoundGracePeriod time.Duration
+
+	// run in order when a TaskRun is deleted, before its finalizer goes
+	cleanupHooks []cleanupHook
+
+	recorder record.EventRecorder
+
+	// where step logs go when a TaskRun finishes, nil keeps them in the Pod
+	logSink logs.Sink
+
+	// delete the Pod once all its step logs are archived
+	deletePodAfterArchive bool
+}
+
+// cleanupHook releases something a deleted TaskRun holds. Hooks must be
+// idempotent: when one fails, all of them run again on the next attempt.
+type cleanupHook struct {
+	name string
+	run  func(tr *miniv1.TaskRun) error
 }
 
 func main() {
-	// Provides shared execution context.
-	ctx := context.Background()
+	defaultTimeout := flag.Duration("default-timeout", time.Hour, "timeout for TaskRuns whose TaskRun and Task set none (0 disables)")
+	taskNotFoundGracePeriod := flag.Duration("task-not-found-grace-period", 5*time.Minute, "how long a TaskRun waits for its referenced Task to be created before it fails (0 fails at once)")
+	logSink := flag.String("log-sink", "none", "where step logs of finished TaskRuns are archived: none, file or configmap")
+	logDir := flag.String("log-dir", "/var/log/minitask", "directory of the file log sink")
+	deletePodAfterArchive := flag.Bool("delete-pod-after-archive", false, "delete the Pod of a finished TaskRun once its step logs are archived")
+	leaderElect := flag.Bool("leader-elect", true, "run workers only while holding the leader Lease, so several replicas can run")
+	leaseName := flag.String("leader-elect-lease-name", "minitask-controller", "name of the leader election Lease")
+	leaseNamespace := flag.String("leader-elect-lease-namespace", "default", "namespace of the leader election Lease")
+	leaseDuration := flag.Duration("leader-elect-lease-duration", 15*time.Second, "how long standby replicas wait before taking over a Lease that is not renewed")
+	renewDeadline := flag.Duration("leader-elect-renew-deadline", 10*time.Second, "how long the leader retries renewing the Lease before it steps down")
+	retryPeriod := flag.Duration("leader-elect-retry-period", 2*time.Second, "how often replicas try to acquire or renew the Lease")
+	flag.Parse()
+
+	// Provides shared execution context, cancelled on SIGINT / SIGTERM so
+	// the Lease is released for the next replica.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
 	// Allows Running controller locally,
 	// Allows controller to talk to cluster
@@ -74,18 +136,52 @@ func main() {
 
 	// creating custom controller, which will act as central orchestrator
 	controller := &Controller{
-		ctx:          ctx,
-		miniClient:   miniClient,
-		coreClient:   coreClient,
-		trInformer:   miniFactory.Minitask().V1().TaskRuns().Informer(),
-		taskInformer: miniFactory.Minitask().V1().Tasks().Informer(),
-		podInformer:  coreFactory.Core().V1().Pods().Informer(),
-		trLister:     miniFactory.Minitask().V1().TaskRuns().Lister(),
-		podLister:    coreFactory.Core().V1().Pods().Lister(),
-		taskLister:   miniFactory.Minitask().V1().Tasks().Lister(),
+		miniClient:          miniClient,
+		coreClient:          coreClient,
+		trInformer:          miniFactory.Minitask().V1().TaskRuns().Informer(),
+		taskInformer:        miniFactory.Minitask().V1().Tasks().Informer(),
+		clusterTaskInformer: miniFactory.Minitask().V1().ClusterTasks().Informer(),
+		podInformer:         coreFactory.Core().V1().Pods().Informer(),
+		trLister:            miniFactory.Minitask().V1().TaskRuns().Lister(),
+		podLister:           coreFactory.Core().V1().Pods().Lister(),
+		taskLister:          miniFactory.Minitask().V1().Tasks().Lister(),
+		clusterTaskLister:   miniFactory.Minitask().V1().ClusterTasks().Lister(),
 		queue: workqueue.NewTypedRateLimitingQueue(
 			workqueue.DefaultTypedControllerRateLimiter[cache.ObjectName](),
 		),
+		defaultTimeout:          *defaultTimeout,
+		taskNotFoundGracePeriod: *taskNotFoundGracePeriod,
+		deletePodAfterArchive:   *deletePodAfterArchive,
+	}
+
+	switch *logSink {
+	case "none":
+	case "file":
+		controller.logSink = &logs.FileSink{Dir: *logDir}
+	case "configmap":
+		controller.logSink = &logs.ConfigMapSink{Client: coreClient}
+	default:
+		fmt.Println("Unknown log sink:", *logSink)
+		os.Exit(1)
+	}
+
+	// events are recorded on TaskRuns, so the scheme must know them
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: coreClient.CoreV1().Events("")})
+	controller.recorder = broadcaster.NewRecorder(minischeme.Scheme, corev1.EventSource{Component: "minitask-controller"})
+
+	controller.cleanupHooks = []cleanupHook{
+		{name: "delete Pods", run: controller.deletePods},
+		{name: "release concurrency slot", run: controller.releaseGroup},
+		{name: "emit final event", run: controller.emitDeletedEvent},
+		{name: "delete archived logs", run: controller.deleteArchivedLogs},
+	}
+
+	// find the TaskRuns waiting for a Task when it is created
+	err = controller.trInformer.AddIndexers(cache.Indexers{resources.TaskRefIndex: resources.TaskRefIndexFunc})
+	if err != nil {
+		fmt.Println("Error adding TaskRun index:", err)
+		os.Exit(1)
 	}
 
 	// attaching event handlers to the informers
@@ -96,7 +192,9 @@ func main() {
 			oldTr := old.(*miniv1.TaskRun)
 			newTr := new.(*miniv1.TaskRun)
 
-			if oldTr.Status.Phase == newTr.Status.Phase {
+			// spec changes (e.g. cancellation) bump the generation
+			if oldTr.Status.Phase == newTr.Status.Phase && oldTr.Generation == newTr.Generation &&
+				(oldTr.DeletionTimestamp != nil) == (newTr.DeletionTimestamp != nil) {
 				return
 			}
 
@@ -110,28 +208,120 @@ func main() {
 		DeleteFunc: controller.handlePodDelete,
 	})
 
+	controller.taskInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleTaskAdd,
+	})
+
+	controller.clusterTaskInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleTaskAdd,
+	})
+
+	if !*leaderElect {
+		controller.run(ctx)
+		return
+	}
+
+	// only the holder of the Lease runs workers, the other replicas wait
+	hostname, err := os.Hostname()
+	if err != nil {
+		fmt.Println("Error getting hostname:", err)
+		os.Exit(1)
+	}
+	identity := hostname + "_" + string(uuid.NewUUID())
+
+	lock := &resourcelock.LeaseLock{
+		LeaseMeta: metav1.ObjectMeta{
+			Name:      *leaseName,
+			Namespace: *leaseNamespace,
+		},
+		Client:     coreClient.CoordinationV1(),
+		LockConfig: resourcelock.ResourceLockConfig{Identity: identity},
+	}
+
+	// the elector calls OnStartedLeading in a goroutine of its own; run is
+	// started from here so the process waits for it to drain the queue
+	leading := make(chan context.Context, 1)
+
+	elector, err := leaderelection.NewLeaderElector(leaderelection.LeaderElectionConfig{
+		Lock:            lock,
+		LeaseDuration:   *leaseDuration,
+		RenewDeadline:   *renewDeadline,
+		RetryPeriod:     *retryPeriod,
+		ReleaseOnCancel: true,
+		Name:            *leaseName,
+		Callbacks: leaderelection.LeaderCallbacks{
+			OnStartedLeading: func(leaderCtx context.Context) {
+				leading <- leaderCtx
+			},
+			OnStoppedLeading: func() {
+				// run drains the queue once its context is cancelled
+				fmt.Println("Leadership lost or released:", identity)
+			},
+			OnNewLeader: func(leader string) {
+				if leader != identity {
+					fmt.Println("Current leader:", leader)
+				}
+			},
+		},
+	})
+	if err != nil {
+		fmt.Println("Error configuring leader election:", err)
+		os.Exit(1)
+	}
+
+	fmt.Println("Waiting for Lease", *leaseNamespace+"/"+*leaseName, "as", identity)
+
+	// the elector returns once leadership is lost or the process is
+	// stopped; exiting lets the replica restart as a fresh candidate with
+	// empty caches
+	electorDone := make(chan struct{})
+	go func() {
+		defer close(electorDone)
+		elector.Run(ctx)
+	}()
+
+	select {
+	case leaderCtx := <-leading:
+		controller.run(leaderCtx)
+	case <-electorDone:
+	}
+
+	// the Lease is released before the elector returns
+	<-electorDone
+}
+
+// run starts the informers and the worker and blocks until ctx is done.
+// In-flight work finishes before the worker stops.
+func (c *Controller) run(ctx context.Context) {
+
+	fmt.Println("Starting controller")
+
+	// reconciles use the context of the leadership term for API calls
+	c.ctx = ctx
+
 	// start informers
-	stopCh := make(chan struct{}) // channel used to stop exec (graceful shutdown)
-	defer close(stopCh)
+	stopCh := ctx.Done() // closed on shutdown or when leadership is lost
 
-	go controller.trInformer.Run(stopCh)
-	go controller.podInformer.Run(stopCh)
-	go controller.taskInformer.Run(stopCh)
+	go c.trInformer.Run(stopCh)
+	go c.podInformer.Run(stopCh)
+	go c.taskInformer.Run(stopCh)
+	go c.clusterTaskInformer.Run(stopCh)
 
 	// wait for initial cache sync
-	if !cache.WaitForCacheSync(stopCh, controller.trInformer.HasSynced, controller.podInformer.HasSynced, controller.taskInformer.HasSynced) {
+	if !cache.WaitForCacheSync(stopCh, c.trInformer.HasSynced, c.podInformer.HasSynced, c.taskInformer.HasSynced, c.clusterTaskInformer.HasSynced) {
 		fmt.Println("Failed to sync caches")
-		os.Exit(1)
+		return
 	}
 
 	// start worker
-	// go controller.runWorker()
-	go controller.runWorker()
-	
+	go c.runWorker()
 
-	// block forever
-	select {}
+	<-ctx.Done()
 
+	// wait for the item in flight, the worker takes no new ones; its API
+	// calls fail fast now that ctx is done
+	fmt.Println("Stopping worker")
+	c.queue.ShutDownWithDrain()
 }
 
 func (c *Controller) enqueueTaskRun(obj interface{}) {
@@ -157,13 +347,16 @@ func (c *Controller) handlePodUpdate(oldObj, newObj interface{}) {
 	oldPod := oldObj.(*corev1.Pod)
 	newPod := newObj.(*corev1.Pod)
 
-	if oldPod.Status.Phase == newPod.Status.Phase {
+	// phase and step (container) changes both affect TaskRun status
+	if oldPod.Status.Phase == newPod.Status.Phase &&
+		equality.Semantic.DeepEqual(oldPod.Status.InitContainerStatuses, newPod.Status.InitContainerStatuses) &&
+		equality.Semantic.DeepEqual(oldPod.Status.ContainerStatuses, newPod.Status.ContainerStatuses) {
 		return
 	}
 
 	pod := newObj.(*corev1.Pod)
 
-	trName := pod.Labels["minitask"]
+	trName := pod.Labels[resources.TaskRunLabel]
 	if trName == "" {
 		return
 	}
@@ -186,7 +379,7 @@ func (c *Controller) handlePodDelete(obj interface{}) {
 		return
 	}
 
-	trName := pod.Labels["minitask"]
+	trName := pod.Labels[resources.TaskRunLabel]
 	if trName == "" {
 		return
 	}
@@ -201,6 +394,40 @@ func (c *Controller) handlePodDelete(obj interface{}) {
 	c.queue.Add(objName)
 }
 
+// handleTaskAdd enqueues the TaskRuns that wait for a newly created Task or
+// ClusterTask.
+func (c *Controller) handleTaskAdd(obj interface{}) {
+
+	var key string
+	switch task := obj.(type) {
+	case *miniv1.Task:
+		key = resources.TaskRefKey(miniv1.TaskKind, task.Namespace, task.Name)
+	case *miniv1.ClusterTask:
+		key = resources.TaskRefKey(miniv1.ClusterTaskKind, "", task.Name)
+	default:
+		return
+	}
+
+	runs, err := c.trInformer.GetIndexer().ByIndex(resources.TaskRefIndex, key)
+	if err != nil {
+		fmt.Println("Error looking up TaskRuns of", key+":", err)
+		return
+	}
+
+	for _, obj := range runs {
+		tr := obj.(*miniv1.TaskRun)
+
+		// only runs that never got a Pod can be waiting for their Task
+		if resources.Finished(tr) || tr.Status.PodName != "" {
+			continue
+		}
+
+		fmt.Println("Task created, enqueue TaskRun:", tr.Namespace+"/"+tr.Name)
+
+		c.queue.Add(cache.ObjectName{Namespace: tr.Namespace, Name: tr.Name})
+	}
+}
+
 func (c *Controller) runWorker() {
 	for c.processNextWorkItem() {
 	}
@@ -242,17 +469,41 @@ func (c *Controller) reconcile(key cache.ObjectName) error {
 
 	fmt.Println("Current Phase:", tr.Status.Phase)
 
+	if tr.DeletionTimestamp != nil {
+		return c.handleDeletedTaskRun(tr)
+	}
+
+	// the finalizer lets the cleanup hooks run once the TaskRun is deleted
+	if !resources.HasFinalizer(tr) {
+		tr, err = c.patchFinalizers(tr, append(slices.Clone(tr.Finalizers), resources.TaskRunFinalizer))
+		if err != nil {
+			return err
+		}
+	}
+
+	if tr.Spec.Status == miniv1.TaskRunSpecStatusCancelled {
+		switch tr.Status.Phase {
+		case "Succeeded", "Failed", "Cancelled":
+		default:
+			return c.cancelTaskRun(tr)
+		}
+	}
+
 	switch tr.Status.Phase {
 
-	case "":
+	case "", "Queued":
 		return c.handleNewTaskRun(tr)
 
 	case "Pending", "Running":
+		// a retried run waits for the Pod of its next attempt
+		if tr.Status.PodName == "" {
+			return c.handleNewTaskRun(tr)
+		}
 		return c.handleActiveTaskRun(tr)
 
-	case "Succeeded", "Failed":
-		fmt.Println("TaskRun already completed. Skipping.")
-		return nil
+	case "Succeeded", "Failed", "Cancelled":
+		fmt.Println("TaskRun already completed.")
+		return c.handleFinishedTaskRun(tr)
 	}
 
 	return nil
@@ -260,18 +511,124 @@ func (c *Controller) reconcile(key cache.ObjectName) error {
 
 func (c *Controller) handleNewTaskRun(tr *miniv1.TaskRun) error {
 
+	// a retried run gets the same spec as its first attempt, even if the
+	// Task changed since
+	if tr.Status.TaskSpec != nil {
+		return c.createPod(tr, tr.Status.TaskSpec, tr.Status.ConcurrencyGroup)
+	}
+
+	namespace := tr.Namespace
+
+	// the checks of the admission webhook, for runs created without it
+	if err := resources.ValidateTaskRun(&tr.Spec); err != nil {
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			return c.failTaskRun(tr, verr.Reason, verr.Message)
+		}
+		return err
+	}
+
+	// inline taskSpec or referenced Task/ClusterTask
+	taskSpec, err := resources.ResolveTaskSpec(tr, c.taskLister.Tasks(namespace).Get, c.clusterTaskLister.Get)
+
+	if apierrors.IsNotFound(err) {
+		fmt.Println("Referenced Task not found")
+		return c.waitForTask(tr)
+	}
+
+	// substitute $(params.*) and reject runs with an invalid task source,
+	// missing or invalid params, an invalid task, unbound workspaces or
+	// invalid concurrency settings
+	var spec *miniv1.TaskSpec
+	if err == nil {
+		spec, err = resources.ApplyParams(taskSpec, tr.Spec.Params)
+	}
+	if err == nil {
+		err = resources.ValidateTask(spec)
+	}
+	if err == nil {
+		err = resources.ValidateWorkspaces(spec, tr.Spec.Workspaces)
+	}
+	var concurrency *miniv1.Concurrency
+	if err == nil {
+		concurrency, err = resources.ResolveConcurrency(tr, taskSpec)
+	}
+	if err != nil {
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			return c.failTaskRun(tr, verr.Reason, verr.Message)
+		}
+		return err
+	}
+
+	group := ""
+	if concurrency != nil {
+		admitted, err := c.admit(tr, concurrency)
+		if err != nil || !admitted {
+			return err
+		}
+		group = concurrency.Group
+	}
+
+	// the snapshot records the timeout in effect, see timeout
+	if tr.Spec.Timeout != nil {
+		spec.Timeout = tr.Spec.Timeout.DeepCopy()
+	} else if spec.Timeout == nil {
+		spec.Timeout = &metav1.Duration{Duration: c.defaultTimeout}
+	}
+
+	return c.createPod(tr, spec, group)
+}
+
+// waitForTask keeps a TaskRun whose Task does not exist Pending with reason
+// TaskNotFound until the grace period, counted from its creation, has
+// passed, and then fails it. handleTaskAdd wakes it up earlier when the
+// Task is created.
+func (c *Controller) waitForTask(tr *miniv1.TaskRun) error {
+
+	kind := tr.Spec.Kind
+	if kind == "" {
+		kind = miniv1.TaskKind
+	}
+
+	remaining := tr.CreationTimestamp.Add(c.taskNotFoundGracePeriod).Sub(time.Now())
+	if remaining <= 0 {
+		return c.failTaskRun(tr, "TaskNotFound", fmt.Sprintf("%s %q not found", kind, tr.Spec.TaskRef))
+	}
+
+	c.queue.AddAfter(cache.ObjectName{Namespace: tr.Namespace, Name: tr.Name}, remaining)
+
+	cond := meta.FindStatusCondition(tr.Status.Conditions, miniv1.TaskRunConditionSucceeded)
+	if cond != nil && cond.Reason == "TaskNotFound" {
+		return nil
+	}
+
+	trCopy := tr.DeepCopy()
+	resources.SetPhase(trCopy, "Pending", "TaskNotFound",
+		fmt.Sprintf("%s %q not found, waiting up to %s for it to be created", kind, tr.Spec.TaskRef, c.taskNotFoundGracePeriod))
+
+	_, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+
+	return err
+}
+
+// createPod creates the Pod of the current attempt of tr from the resolved
+// spec and records spec as the snapshot of what the run executes.
+func (c *Controller) createPod(tr *miniv1.TaskRun, spec *miniv1.TaskSpec, group string) error {
+
 	// create pod
 
 	namespace := tr.Namespace
-	podName := tr.Name + "-pod"
+	podName := resources.PodName(tr)
 
 	fmt.Println("Creating Pod for:", tr.Name)
 
 	_, err := c.podLister.Pods(namespace).Get(podName)
 
 	if err == nil {
+		// created by an earlier reconcile whose status update failed
 		fmt.Println("Pod already exists. Skipping creation.")
-		return nil
+		return c.markPodCreated(tr, podName, group, spec)
 	}
 
 	if !apierrors.IsNotFound(err) {
@@ -279,113 +636,661 @@ func (c *Controller) handleNewTaskRun(tr *miniv1.TaskRun) error {
 		return err
 	}
 
-	task, err := c.taskLister.
-		Tasks(namespace).
-		Get(tr.Spec.TaskRef)
+	// volumeClaimTemplate workspaces need their PVC before the Pod
+	for _, pvc := range resources.MakeWorkspacePVCs(tr) {
+		_, err := c.coreClient.CoreV1().PersistentVolumeClaims(namespace).Create(c.ctx, pvc, metav1.CreateOptions{})
+		if err != nil && !apierrors.IsAlreadyExists(err) {
+			return err
+		}
+	}
 
+	// namespace-wide podTemplate, overridden by the TaskRun's own
+	defaults, err := c.defaultPodTemplate(namespace)
 	if err != nil {
-		if apierrors.IsNotFound(err) {
-			fmt.Println("Referenced Task not found")
-			return nil
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			return c.failTaskRun(tr, verr.Reason, verr.Message)
 		}
 		return err
 	}
 
-	var containers []corev1.Container
+	// steps run in declared order, see resources.MakePod
+	pod := resources.MakePod(tr, spec, defaults)
 
-	for _, step := range task.Spec.Steps {
-		container := corev1.Container{
-			Name:    step.Name,
-			Image:   step.Image,
-			Command: []string{"/bin/sh", "-c"},
-			Args:    []string{step.Script},
+	_, err = c.coreClient.CoreV1().Pods(namespace).Create(c.ctx, pod, metav1.CreateOptions{})
+	if err != nil {
+		// handle race condition
+		if apierrors.IsAlreadyExists(err) {
+			fmt.Println("Pod already exists (race). Skipping.")
+			return c.markPodCreated(tr, podName, group, spec)
+		}
+		// the API server rejected the Pod spec; retrying cannot fix it
+		if apierrors.IsInvalid(err) {
+			return c.failTaskRun(tr, "PodCreationFailed", err.Error())
 		}
-		containers = append(containers, container)
+		return err
 	}
 
-	pod := &corev1.Pod{
-		ObjectMeta: metav1.ObjectMeta{
-			Name:      podName,
-			Namespace: namespace,
-			Labels: map[string]string{
-				"minitask": tr.Name,
-			},
-			OwnerReferences: []metav1.OwnerReference{
-				*metav1.NewControllerRef(
-					tr,
-					miniv1.SchemeGroupVersion.WithKind("TaskRun"),
-				),
-			},
-		},
-		Spec: corev1.PodSpec{
-			RestartPolicy: corev1.RestartPolicyNever,
-			Containers:    containers,
+	fmt.Println("Pod created:", podName)
+
+	return c.markPodCreated(tr, podName, group, spec)
+}
+
+// admit checks tr against its concurrency group. When the group is full it
+// applies the group policy: Queue and CancelInProgress move tr to Queued,
+// the latter after cancelling the runs ahead of it, and Reject fails it.
+func (c *Controller) admit(tr *miniv1.TaskRun, concurrency *miniv1.Concurrency) (bool, error) {
+
+	// read the group from the API server rather than the cache, which may
+	// not show a run admitted just before as Pending yet
+	list, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).List(c.ctx, metav1.ListOptions{})
+	if err != nil {
+		return false, err
+	}
+
+	var members []*miniv1.TaskRun
+	for i := range list.Items {
+		member := &list.Items[i]
+		if member.Name != tr.Name && member.Status.ConcurrencyGroup == concurrency.Group {
+			members = append(members, member)
+		}
+	}
+
+	admit, running, ahead := resources.Admit(concurrency, tr, members)
+	if admit {
+		return true, nil
+	}
+
+	switch concurrency.Policy {
+
+	case miniv1.ConcurrencyReject:
+		return false, c.failTaskRun(tr, "ConcurrencyLimitReached",
+			fmt.Sprintf("concurrency group %q already runs %d of %d", concurrency.Group, len(running), concurrency.MaxRunning))
+
+	case miniv1.ConcurrencyCancelInProgress:
+		for _, member := range append(running, ahead...) {
+			if member.Spec.Status == miniv1.TaskRunSpecStatusCancelled {
+				continue
+			}
+
+			fmt.Println("Cancelling TaskRun in concurrency group:", member.Name)
+
+			patch := fmt.Sprintf(`{"spec":{"status":%q}}`, miniv1.TaskRunSpecStatusCancelled)
+			_, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).Patch(c.ctx, member.Name, types.MergePatchType, []byte(patch), metav1.PatchOptions{})
+			if err != nil && !apierrors.IsNotFound(err) {
+				return false, err
+			}
+		}
+	}
+
+	if tr.Status.Phase == "Queued" && tr.Status.ConcurrencyGroup == concurrency.Group {
+		fmt.Println("Concurrency group still full. Waiting.")
+		return false, nil
+	}
+
+	fmt.Println("Concurrency group full. Queueing TaskRun.")
+
+	trCopy := tr.DeepCopy()
+	resources.SetPhase(trCopy, "Queued", "Queued",
+		fmt.Sprintf("waiting for a slot in concurrency group %q (%d running, %d queued ahead)", concurrency.Group, len(running), len(ahead)))
+	trCopy.Status.ConcurrencyGroup = concurrency.Group
+
+	_, err = c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+
+	return false, err
+}
+
+// handleDeletedTaskRun runs the cleanup hooks of a deleted TaskRun and then
+// removes its finalizer, so the deletion can complete.
+func (c *Controller) handleDeletedTaskRun(tr *miniv1.TaskRun) error {
+
+	if !resources.HasFinalizer(tr) {
+		return nil
+	}
+
+	fmt.Println("TaskRun deleted. Running cleanup hooks.")
+
+	for _, hook := range c.cleanupHooks {
+		if err := hook.run(tr); err != nil {
+			return fmt.Errorf("cleanup hook %q: %w", hook.name, err)
+		}
+	}
+
+	_, err := c.patchFinalizers(tr, resources.RemoveFinalizer(tr))
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
+
+// patchFinalizers sets the finalizers of tr with a merge patch, which
+// leaves the spec alone. The resourceVersion makes the patch fail on a
+// conflict rather than drop a finalizer added by someone else meanwhile.
+func (c *Controller) patchFinalizers(tr *miniv1.TaskRun, finalizers []string) (*miniv1.TaskRun, error) {
+
+	patch, err := json.Marshal(map[string]interface{}{
+		"metadata": map[string]interface{}{
+			"finalizers":      finalizers,
+			"resourceVersion": tr.ResourceVersion,
 		},
+	})
+	if err != nil {
+		return nil, err
 	}
 
-	_, err = c.coreClient.CoreV1().Pods(namespace).Create(c.ctx, pod, metav1.CreateOptions{})
+	return c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).Patch(c.ctx, tr.Name, types.MergePatchType, patch, metav1.PatchOptions{})
+}
+
+// deletePods deletes the Pods of every attempt of a deleted TaskRun rather
+// than waiting for the garbage collector.
+func (c *Controller) deletePods(tr *miniv1.TaskRun) error {
+
+	pods, err := c.podLister.Pods(tr.Namespace).List(
+		labels.SelectorFromSet(labels.Set{resources.TaskRunLabel: tr.Name}),
+	)
 	if err != nil {
-		// handle race condition
-		if apierrors.IsAlreadyExists(err) {
-			fmt.Println("Pod already exists (race). Skipping.")
-			return nil
+		return err
+	}
+
+	for _, pod := range pods {
+		fmt.Println("Deleting Pod:", pod.Name)
+
+		err := c.coreClient.CoreV1().Pods(tr.Namespace).Delete(c.ctx, pod.Name, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
 		}
+	}
+
+	return nil
+}
+
+// emitDeletedEvent records the final state of a deleted TaskRun.
+func (c *Controller) emitDeletedEvent(tr *miniv1.TaskRun) error {
+
+	phase := tr.Status.Phase
+	if phase == "" {
+		phase = "not started"
+	}
+
+	c.recorder.Eventf(tr, corev1.EventTypeNormal, "Deleted", "TaskRun deleted in phase %s", phase)
+
+	return nil
+}
+
+// releaseGroup wakes up the Queued runs of the concurrency group of a
+// finished or deleted TaskRun, so the next ones can take its slot.
+func (c *Controller) releaseGroup(tr *miniv1.TaskRun) error {
+
+	if tr.Status.ConcurrencyGroup == "" {
+		return nil
+	}
+
+	runs, err := c.trLister.TaskRuns(tr.Namespace).List(labels.Everything())
+	if err != nil {
 		return err
 	}
 
-	fmt.Println("Pod created:", podName)
+	for _, run := range runs {
+		if run.Status.Phase == "Queued" && run.Status.ConcurrencyGroup == tr.Status.ConcurrencyGroup {
+			c.queue.Add(cache.ObjectName{Namespace: run.Namespace, Name: run.Name})
+		}
+	}
 
-	// update status
+	return nil
+}
+
+// defaultPodTemplate reads the default podTemplate of a namespace from its
+// defaults ConfigMap, if there is one.
+func (c *Controller) defaultPodTemplate(namespace string) (*miniv1.PodTemplate, error) {
+
+	cm, err := c.coreClient.CoreV1().ConfigMaps(namespace).Get(c.ctx, resources.DefaultsConfigMapName, metav1.GetOptions{})
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return resources.ParsePodTemplate(cm)
+}
+
+// markPodCreated moves tr to Pending on the Pod of its current attempt,
+// holding a slot in its concurrency group, if any, and records the spec
+// snapshot the Pod was built from.
+func (c *Controller) markPodCreated(tr *miniv1.TaskRun, podName, group string, spec *miniv1.TaskSpec) error {
 
 	trCopy := tr.DeepCopy()
-	trCopy.Status.Phase = "Pending"
+	resources.SetPhase(trCopy, "Pending", "PodCreated", "Pod "+podName+" created")
 	trCopy.Status.PodName = podName
+	trCopy.Status.ConcurrencyGroup = group
+	trCopy.Status.TaskSpec = spec.DeepCopy()
+	// the timeout counts from the first attempt, so time spent Pending
+	// and in earlier attempts is included
+	if trCopy.Status.StartTime == nil {
+		now := metav1.Now()
+		trCopy.Status.StartTime = &now
+	}
 
-	_, err = c.miniClient.MinitaskV1().TaskRuns(namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+	_, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
 
 	return err
 }
 
-func (c *Controller) handleActiveTaskRun(tr *miniv1.TaskRun) error {
+func isValidationError(err error) bool {
+	var verr *resources.ValidationError
+	return errors.As(err, &verr)
+}
 
-	namespace := tr.Namespace
-	podName := tr.Status.PodName
+// failTaskRun marks a TaskRun that cannot run as Failed.
+func (c *Controller) failTaskRun(tr *miniv1.TaskRun, reason, message string) error {
 
-	fmt.Println("Checking Pod status...")
+	fmt.Println("Marking TaskRun as Failed:", message)
 
-	pod, err := c.podLister.Pods(namespace).Get(podName)
+	trCopy := tr.DeepCopy()
+	resources.SetPhase(trCopy, "Failed", reason, message)
+	now := metav1.Now()
+	trCopy.Status.FinishTime = &now
+
+	_, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+
+	return err
+}
+
+// timeout returns how long tr may run: the timeout recorded in its spec
+// snapshot, else its own timeout, else the one of its Task or inline
+// taskSpec, else the controller default. 0 means no timeout.
+func (c *Controller) timeout(tr *miniv1.TaskRun) (time.Duration, error) {
+
+	if tr.Status.TaskSpec != nil && tr.Status.TaskSpec.Timeout != nil {
+		return tr.Status.TaskSpec.Timeout.Duration, nil
+	}
+
+	if tr.Spec.Timeout != nil {
+		return tr.Spec.Timeout.Duration, nil
+	}
+
+	spec, err := resources.ResolveTaskSpec(tr, c.taskLister.Tasks(tr.Namespace).Get, c.clusterTaskLister.Get)
+	if err != nil && !apierrors.IsNotFound(err) && !isValidationError(err) {
+		return 0, err
+	}
+
+	if err == nil && spec.Timeout != nil {
+		return spec.Timeout.Duration, nil
+	}
+
+	return c.defaultTimeout, nil
+}
+
+// timeOutTaskRun kills the Pod of a TaskRun that ran past its deadline and
+// marks it Failed with reason TimedOut.
+func (c *Controller) timeOutTaskRun(tr *miniv1.TaskRun, timeout time.Duration) error {
+
+	trCopy := c.saveSteps(tr)
+
+	fmt.Println("TaskRun timed out. Deleting Pod:", tr.Status.PodName)
+
+	err := c.coreClient.CoreV1().Pods(tr.Namespace).Delete(c.ctx, tr.Status.PodName, metav1.DeleteOptions{})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return c.failTaskRun(trCopy, "TimedOut", fmt.Sprintf("TaskRun exceeded its timeout of %s", timeout))
+}
+
+// cancelTaskRun deletes the Pod of a cancelled TaskRun and moves it to
+// the Cancelled phase.
+func (c *Controller) cancelTaskRun(tr *miniv1.TaskRun) error {
+
+	trCopy := c.saveSteps(tr)
+
+	if tr.Status.PodName != "" {
+		fmt.Println("TaskRun cancelled. Deleting Pod:", tr.Status.PodName)
+
+		err := c.coreClient.CoreV1().Pods(tr.Namespace).Delete(c.ctx, tr.Status.PodName, metav1.DeleteOptions{})
+		if err != nil && !apierrors.IsNotFound(err) {
+			return err
+		}
+	}
+
+	resources.SetPhase(trCopy, "Cancelled", "Cancelled", "TaskRun was cancelled")
+	now := metav1.Now()
+	trCopy.Status.FinishTime = &now
+
+	_, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+
+	return err
+}
+
+// handleFinishedTaskRun releases the concurrency slot of tr, archives its
+// step logs, deletes tr once its ttlSecondsAfterFinished has passed, and
+// deletes the oldest finished runs of its Task beyond the Task's history
+// limits.
+func (c *Controller) handleFinishedTaskRun(tr *miniv1.TaskRun) error {
+
+	if err := c.releaseGroup(tr); err != nil {
+		return err
+	}
+
+	// a log that cannot be archived must not keep the run from its TTL and
+	// history limits
+	if err := c.archiveLogs(tr); err != nil {
+		fmt.Println("Error archiving logs:", err)
+		c.recorder.Eventf(tr, corev1.EventTypeWarning, "LogArchiveFailed", "Step logs not archived: %v", err)
+	}
+
+	if remaining, ok := resources.TTLRemaining(tr, time.Now()); ok {
+		if remaining <= 0 {
+			fmt.Println("TaskRun TTL expired.")
+			return c.deleteTaskRun(tr)
+		}
+		c.queue.AddAfter(cache.ObjectName{Namespace: tr.Namespace, Name: tr.Name}, remaining)
+	}
+
+	if tr.Spec.TaskRef == "" {
+		return nil
+	}
+
+	spec, err := resources.ResolveTaskSpec(tr, c.taskLister.Tasks(tr.Namespace).Get, c.clusterTaskLister.Get)
 	if err != nil {
+		if apierrors.IsNotFound(err) || isValidationError(err) {
+			return nil
+		}
+		return err
+	}
+
+	if spec.SuccessfulRunsHistoryLimit == nil && spec.FailedRunsHistoryLimit == nil {
+		return nil
+	}
+
+	all, err := c.trLister.TaskRuns(tr.Namespace).List(labels.Everything())
+	if err != nil {
+		return err
+	}
+
+	var runs []*miniv1.TaskRun
+	for _, run := range all {
+		if resources.SameTask(tr, run) && run.DeletionTimestamp == nil {
+			runs = append(runs, run)
+		}
+	}
+
+	for _, old := range resources.OverHistoryLimit(spec, runs) {
+		fmt.Println("TaskRun over history limit of", tr.Spec.TaskRef+".")
+		if err := c.deleteTaskRun(old); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// saveSteps returns a copy of tr with the final step states of its Pod and
+// the logs of the steps that ran archived, for a run whose Pod is about to
+// be deleted. A log that cannot be archived does not keep the Pod alive.
+func (c *Controller) saveSteps(tr *miniv1.TaskRun) *miniv1.TaskRun {
+
+	trCopy := tr.DeepCopy()
+
+	if tr.Status.PodName == "" {
+		return trCopy
+	}
+
+	pod, err := c.podLister.Pods(tr.Namespace).Get(tr.Status.PodName)
+	if err != nil {
+		return trCopy
+	}
+
+	trCopy.Status.Steps = resources.StepStates(pod)
+
+	if _, err := c.storeLogs(trCopy, pod.Name, trCopy.Status.Steps, 0); err != nil {
+		fmt.Println("Error archiving logs:", err)
+		c.recorder.Eventf(tr, corev1.EventTypeWarning, "LogArchiveFailed", "Step logs not archived: %v", err)
+	}
+
+	return trCopy
+}
+
+// storeLogs copies the log of every step in steps that ran from the Pod
+// podName to the log sink and records where it went in steps. attempt is
+// the failed attempt the Pod belongs to, 0 for the last one. A step that
+// fails does not stop the others. It reports whether any log was stored.
+func (c *Controller) storeLogs(tr *miniv1.TaskRun, podName string, steps []miniv1.StepState, attempt int) (bool, error) {
+
+	if c.logSink == nil {
+		return false, nil
+	}
+
+	archived := false
+	var errs []error
 
+	for i, step := range steps {
+		if step.State == "Waiting" || step.LogLocation != "" {
+			continue
+		}
+
+		log, err := c.coreClient.CoreV1().Pods(tr.Namespace).GetLogs(
+			podName,
+			&corev1.PodLogOptions{Container: step.ContainerName},
+		).DoRaw(c.ctx)
 		if apierrors.IsNotFound(err) {
-			fmt.Println("Pod missing. Marking TaskRun as Failed.")
+			fmt.Println("Pod gone. Logs of step", step.Name, "not archived.")
+			continue
+		}
+		if err == nil {
+			name := step.Name
+			if attempt > 0 {
+				name = logs.AttemptStep(step.Name, attempt)
+			}
+			steps[i].LogLocation, err = c.logSink.Store(c.ctx, tr, name, log)
+		}
+		if err != nil {
+			// the other steps are still archived
+			errs = append(errs, fmt.Errorf("step %q: %w", step.Name, err))
+			continue
+		}
 
-			trCopy := tr.DeepCopy()
-			trCopy.Status.Phase = "Failed"
-			now := metav1.Now()
-			trCopy.Status.FinishTime = &now
+		fmt.Println("Archived log of step", step.Name, "to", steps[i].LogLocation)
+
+		archived = true
+	}
 
-			_, updateErr := c.miniClient.MinitaskV1().TaskRuns(namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+	return archived, errors.Join(errs...)
+}
 
+// archiveLogs copies the log of every terminated step of a finished TaskRun
+// from its Pod to the log sink and records where it went. A step that fails
+// does not stop the others; the Pod is kept until all are archived.
+// Cancelled and timed-out runs had theirs archived before the Pod was
+// deleted.
+func (c *Controller) archiveLogs(tr *miniv1.TaskRun) error {
+
+	if c.logSink == nil || tr.Status.PodName == "" {
+		return nil
+	}
+
+	trCopy := tr.DeepCopy()
+
+	archived, err := c.storeLogs(trCopy, tr.Status.PodName, trCopy.Status.Steps, 0)
+	if archived {
+		_, updateErr := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+		if updateErr != nil {
 			return updateErr
 		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !c.deletePodAfterArchive {
+		return nil
+	}
+
+	for _, step := range trCopy.Status.Steps {
+		if step.State != "Waiting" && step.LogLocation == "" {
+			return nil
+		}
+	}
+
+	err = c.coreClient.CoreV1().Pods(tr.Namespace).Delete(c.ctx, tr.Status.PodName, metav1.DeleteOptions{})
+	if err == nil {
+		fmt.Println("Logs archived. Deleted Pod:", tr.Status.PodName)
+	} else if !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// deleteArchivedLogs removes the archived step logs of a deleted TaskRun.
+func (c *Controller) deleteArchivedLogs(tr *miniv1.TaskRun) error {
+
+	if c.logSink == nil {
+		return nil
+	}
+
+	return c.logSink.Delete(c.ctx, tr)
+}
+
+// deleteTaskRun deletes a finished TaskRun. Its Pod and workspace PVCs are
+// owned by it and are garbage collected with it.
+func (c *Controller) deleteTaskRun(tr *miniv1.TaskRun) error {
+
+	fmt.Println("Deleting TaskRun:", tr.Name)
+
+	policy := metav1.DeletePropagationBackground
+	err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).Delete(c.ctx, tr.Name, metav1.DeleteOptions{PropagationPolicy: &policy})
+	if err != nil && !apierrors.IsNotFound(err) {
+		return err
+	}
+
+	return nil
+}
+
+// canRetry reports whether tr has attempts left after the current one.
+func (c *Controller) canRetry(tr *miniv1.TaskRun) bool {
+	return len(tr.Status.RetriesStatus) < tr.Spec.Retries
+}
+
+// retryTaskRun records the failed attempt in retriesStatus, with its step
+// states and archived logs, and requeues tr so that handleNewTaskRun creates
+// a fresh Pod for the next attempt. pod is nil when the Pod of the failed
+// attempt is gone.
+func (c *Controller) retryTaskRun(tr *miniv1.TaskRun, pod *corev1.Pod, reason, message string) error {
+
+	n := len(tr.Status.RetriesStatus) + 1
+
+	now := metav1.Now()
+	attempt := miniv1.TaskRunAttempt{
+		PodName:    tr.Status.PodName,
+		Phase:      "Failed",
+		Reason:     reason,
+		Message:    message,
+		FinishTime: &now,
+	}
+	if pod != nil {
+		attempt.StartTime = pod.Status.StartTime.DeepCopy()
+		attempt.Steps = resources.StepStates(pod)
+
+		// the next attempt replaces the Pod, and its logs with it
+		if _, err := c.storeLogs(tr, pod.Name, attempt.Steps, n); err != nil {
+			fmt.Println("Error archiving logs:", err)
+			c.recorder.Eventf(tr, corev1.EventTypeWarning, "LogArchiveFailed", "Step logs of attempt %d not archived: %v", n, err)
+		}
+	}
+
+	trCopy := tr.DeepCopy()
+	trCopy.Status.RetriesStatus = append(trCopy.Status.RetriesStatus, attempt)
+	trCopy.Status.PodName = ""
+	trCopy.Status.Steps = nil
+	trCopy.Status.Results = nil
+	fmt.Printf("Attempt %d of %d failed: %s. Retrying.\n", n, tr.Spec.Retries+1, message)
+
+	resources.SetPhase(trCopy, "Pending", "Retrying",
+		fmt.Sprintf("attempt %d of %d failed: %s", n, tr.Spec.Retries+1, message))
+
+	_, err := c.miniClient.MinitaskV1().TaskRuns(tr.Namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+	if err != nil {
+		return err
+	}
+
+	c.queue.Add(cache.ObjectName{Namespace: tr.Namespace, Name: tr.Name})
+	return nil
+}
+
+func (c *Controller) handleActiveTaskRun(tr *miniv1.TaskRun) error {
+
+	namespace := tr.Namespace
+	podName := tr.Status.PodName
+
+	fmt.Println("Checking Pod status...")
 
+	pod, err := c.podLister.Pods(namespace).Get(podName)
+	if err != nil && !apierrors.IsNotFound(err) {
 		fmt.Println("Error fetching Pod:", err)
 		return err
 	}
 
+	// a Pod that finished before the deadline keeps its outcome, even when
+	// it is reconciled after the deadline
+	finished := err == nil && (pod.Status.Phase == corev1.PodSucceeded || pod.Status.Phase == corev1.PodFailed)
+
+	if !finished {
+		timeout, err := c.timeout(tr)
+		if err != nil {
+			return err
+		}
+
+		if timeout > 0 && tr.Status.StartTime != nil {
+			remaining := time.Until(tr.Status.StartTime.Add(timeout))
+			if remaining <= 0 {
+				return c.timeOutTaskRun(tr, timeout)
+			}
+
+			// come back when the deadline passes, even if the Pod never changes
+			c.queue.AddAfter(cache.ObjectName{Namespace: namespace, Name: tr.Name}, remaining)
+		}
+	}
+
+	if apierrors.IsNotFound(err) {
+		if c.canRetry(tr) {
+			return c.retryTaskRun(tr, nil, "PodMissing", "Pod "+podName+" no longer exists")
+		}
+
+		fmt.Println("Pod missing. Marking TaskRun as Failed.")
+
+		trCopy := tr.DeepCopy()
+		resources.SetPhase(trCopy, "Failed", "PodMissing", "Pod "+podName+" no longer exists")
+		now := metav1.Now()
+		trCopy.Status.FinishTime = &now
+
+		_, updateErr := c.miniClient.MinitaskV1().TaskRuns(namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
+
+		return updateErr
+	}
+
 	fmt.Println("Pod Phase:", pod.Status.Phase)
 
 	oldPhase := tr.Status.Phase
 	newPhase := oldPhase
+	var reason, message string
 	trCopy := tr.DeepCopy()
 
 	switch pod.Status.Phase {
 
 	case corev1.PodPending:
-		newPhase = "Pending"
+		// the pod stays Pending while the init container steps run
+		if !resources.StepsStarted(pod) {
+			newPhase = "Pending"
+			message = "Waiting for Pod to start"
+			break
+		}
+		fallthrough
 
 	case corev1.PodRunning:
 		newPhase = "Running"
+		message = "Steps are running"
 		if trCopy.Status.StartTime == nil {
 			now := metav1.Now()
 			trCopy.Status.StartTime = &now
@@ -393,20 +1298,41 @@ func (c *Controller) handleActiveTaskRun(tr *miniv1.TaskRun) error {
 
 	case corev1.PodSucceeded:
 		newPhase = "Succeeded"
+		message = "All steps completed successfully"
 		now := metav1.Now()
 		trCopy.Status.FinishTime = &now
 
+		// a declared result that was not written fails the run
+		results, err := resources.ParseResults(pod)
+		trCopy.Status.Results = results
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			newPhase = "Failed"
+			reason, message = verr.Reason, verr.Message
+		}
+
 	case corev1.PodFailed:
 		newPhase = "Failed"
+		reason, message = resources.PodFailure(pod)
+		if c.canRetry(tr) {
+			return c.retryTaskRun(tr, pod, reason, message)
+		}
 		now := metav1.Now()
 		trCopy.Status.FinishTime = &now
 	}
 
-	if oldPhase != newPhase {
+	trCopy.Status.Steps = resources.StepStates(pod)
+	stepsChanged := !equality.Semantic.DeepEqual(tr.Status.Steps, trCopy.Status.Steps)
+
+	if oldPhase != newPhase || stepsChanged || tr.Status.ObservedGeneration != tr.Generation {
 
-		fmt.Printf("Phase Transition %s -> %s\n", oldPhase, newPhase)
+		if oldPhase != newPhase {
+			fmt.Printf("Phase Transition %s -> %s\n", oldPhase, newPhase)
+		} else {
+			fmt.Println("Step states changed")
+		}
 
-		trCopy.Status.Phase = newPhase
+		resources.SetPhase(trCopy, newPhase, reason, message)
 
 		_, err := c.miniClient.MinitaskV1().TaskRuns(namespace).UpdateStatus(c.ctx, trCopy, metav1.UpdateOptions{})
 
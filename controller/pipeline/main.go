@@ -0,0 +1,408 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	miniclient "github.com/ankrsinha/mini-task/pkg/generated/clientset/versioned"
+	miniInformers "github.com/ankrsinha/mini-task/pkg/generated/informers/externalversions"
+	minilisterv1 "github.com/ankrsinha/mini-task/pkg/generated/listers/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/pipeline"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	"k8s.io/apimachinery/pkg/api/equality"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
+
+	"k8s.io/client-go/tools/cache"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/workqueue"
+)
+
+// Controller runs PipelineRuns: it creates one TaskRun per Pipeline task in
+// dependency order and aggregates their phases. The TaskRuns themselves are
+// run by the TaskRun controller.
+type Controller struct {
+	ctx context.Context
+
+	miniClient *miniclient.Clientset
+
+	prInformer       cache.SharedIndexInformer
+	pipelineInformer cache.SharedIndexInformer
+	trInformer       cache.SharedIndexInformer
+
+	prLister       minilisterv1.PipelineRunLister
+	pipelineLister minilisterv1.PipelineLister
+	trLister       minilisterv1.TaskRunLister
+
+	queue workqueue.TypedRateLimitingInterface[cache.ObjectName]
+}
+
+func main() {
+	// Provides shared execution context.
+	ctx := context.Background()
+
+	kubeconfig := clientcmd.RecommendedHomeFile
+	config, err := clientcmd.BuildConfigFromFlags("", kubeconfig)
+	if err != nil {
+		fmt.Println("Error building kubeconfig:", err)
+		os.Exit(1)
+	}
+
+	// create generated client
+	miniClient, err := miniclient.NewForConfig(config)
+	if err != nil {
+		fmt.Println("Error creating mini client:", err)
+		os.Exit(1)
+	}
+
+	miniFactory := miniInformers.NewSharedInformerFactory(miniClient, 0)
+
+	controller := &Controller{
+		ctx:              ctx,
+		miniClient:       miniClient,
+		prInformer:       miniFactory.Minitask().V1().PipelineRuns().Informer(),
+		pipelineInformer: miniFactory.Minitask().V1().Pipelines().Informer(),
+		trInformer:       miniFactory.Minitask().V1().TaskRuns().Informer(),
+		prLister:         miniFactory.Minitask().V1().PipelineRuns().Lister(),
+		pipelineLister:   miniFactory.Minitask().V1().Pipelines().Lister(),
+		trLister:         miniFactory.Minitask().V1().TaskRuns().Lister(),
+		queue: workqueue.NewTypedRateLimitingQueue(
+			workqueue.DefaultTypedControllerRateLimiter[cache.ObjectName](),
+		),
+	}
+
+	// attaching event handlers to the informers
+
+	controller.prInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.enqueuePipelineRun,
+		UpdateFunc: func(old, new interface{}) {
+			oldPr := old.(*miniv1.PipelineRun)
+			newPr := new.(*miniv1.PipelineRun)
+
+			if oldPr.Status.Phase == newPr.Status.Phase && oldPr.Generation == newPr.Generation {
+				return
+			}
+
+			controller.enqueuePipelineRun(new)
+		},
+		DeleteFunc: controller.enqueuePipelineRun,
+	})
+
+	// child TaskRuns wake up their PipelineRun when their phase changes
+	controller.trInformer.AddEventHandler(cache.ResourceEventHandlerFuncs{
+		AddFunc: controller.handleTaskRun,
+		UpdateFunc: func(old, new interface{}) {
+			oldTr := old.(*miniv1.TaskRun)
+			newTr := new.(*miniv1.TaskRun)
+
+			if oldTr.Status.Phase == newTr.Status.Phase {
+				return
+			}
+
+			controller.handleTaskRun(new)
+		},
+		DeleteFunc: controller.handleTaskRun,
+	})
+
+	// start informers
+	stopCh := make(chan struct{}) // channel used to stop exec (graceful shutdown)
+	defer close(stopCh)
+
+	go controller.prInformer.Run(stopCh)
+	go controller.pipelineInformer.Run(stopCh)
+	go controller.trInformer.Run(stopCh)
+
+	// wait for initial cache sync
+	if !cache.WaitForCacheSync(stopCh, controller.prInformer.HasSynced, controller.pipelineInformer.HasSynced, controller.trInformer.HasSynced) {
+		fmt.Println("Failed to sync caches")
+		os.Exit(1)
+	}
+
+	// start worker
+	go controller.runWorker()
+
+	// block forever
+	select {}
+}
+
+func (c *Controller) enqueuePipelineRun(obj interface{}) {
+	key, err := cache.MetaNamespaceKeyFunc(obj)
+	if err != nil {
+		return
+	}
+
+	namespace, name, err := cache.SplitMetaNamespaceKey(key)
+	if err != nil {
+		return
+	}
+
+	c.queue.Add(cache.ObjectName{Namespace: namespace, Name: name})
+}
+
+func (c *Controller) handleTaskRun(obj interface{}) {
+
+	tr, ok := obj.(*miniv1.TaskRun)
+	if !ok {
+		return
+	}
+
+	prName := tr.Labels[pipeline.PipelineRunLabel]
+	if prName == "" {
+		return
+	}
+
+	fmt.Println("TaskRun changed, enqueue PipelineRun:", tr.Namespace+"/"+prName)
+
+	c.queue.Add(cache.ObjectName{Namespace: tr.Namespace, Name: prName})
+}
+
+func (c *Controller) runWorker() {
+	for c.processNextWorkItem() {
+	}
+}
+
+func (c *Controller) processNextWorkItem() bool {
+
+	key, shutdown := c.queue.Get()
+	if shutdown {
+		return false
+	}
+
+	defer c.queue.Done(key)
+
+	err := c.reconcile(key)
+	if err != nil {
+		fmt.Println("Error reconciling:", err)
+		c.queue.AddRateLimited(key)
+		return true
+	}
+
+	c.queue.Forget(key)
+	return true
+}
+
+func (c *Controller) reconcile(key cache.ObjectName) error {
+
+	namespace := key.Namespace
+	name := key.Name
+
+	fmt.Println("--------------------------------------------------")
+	fmt.Println("Reconciling PipelineRun:", name)
+
+	pr, err := c.prLister.PipelineRuns(namespace).Get(name)
+	if err != nil {
+		fmt.Println("PipelineRun not found")
+		return nil
+	}
+
+	fmt.Println("Current Phase:", pr.Status.Phase)
+
+	switch pr.Status.Phase {
+	case "Succeeded", "Failed":
+		fmt.Println("PipelineRun already completed. Skipping.")
+		return nil
+	}
+
+	p, err := c.pipelineLister.Pipelines(namespace).Get(pr.Spec.PipelineRef)
+	if err != nil {
+		if apierrors.IsNotFound(err) {
+			return c.failPipelineRun(pr, "PipelineNotFound", "Pipeline "+pr.Spec.PipelineRef+" not found")
+		}
+		return err
+	}
+
+	// reject invalid graphs and missing or invalid params
+	err = pipeline.Validate(&p.Spec)
+	var params map[string]miniv1.ParamValue
+	if err == nil {
+		params, err = resources.ResolveParams(p.Spec.Params, pr.Spec.Params)
+	}
+	if err != nil {
+		var verr *resources.ValidationError
+		if errors.As(err, &verr) {
+			return c.failPipelineRun(pr, verr.Reason, verr.Message)
+		}
+		return err
+	}
+
+	return c.runTasks(pr, &p.Spec, params)
+}
+
+// runTasks creates the TaskRuns of every task that is ready, unless a task
+// failed already, and updates the PipelineRun status from its children.
+func (c *Controller) runTasks(pr *miniv1.PipelineRun, spec *miniv1.PipelineSpec, params map[string]miniv1.ParamValue) error {
+
+	children, err := c.trLister.TaskRuns(pr.Namespace).List(
+		labels.SelectorFromSet(labels.Set{pipeline.PipelineRunLabel: pr.Name}),
+	)
+	if err != nil {
+		return err
+	}
+
+	byTask := map[string]*miniv1.TaskRun{}
+	phases := map[string]string{}
+	results := map[string][]miniv1.TaskRunResult{}
+
+	for _, tr := range children {
+		taskName := tr.Labels[pipeline.PipelineTaskLabel]
+		byTask[taskName] = tr
+		phases[taskName] = tr.Status.Phase
+		results[taskName] = tr.Status.Results
+	}
+
+	// status.childTaskRuns records the tasks that started. A recorded child
+	// that is gone was deleted (by hand, by a TTL or a history limit) and
+	// counts as failed rather than as never started, so it is not run again
+	deleted := map[string]string{}
+	for _, child := range pr.Status.ChildTaskRuns {
+		if _, ok := byTask[child.PipelineTaskName]; ok {
+			continue
+		}
+
+		// the cache may not show a child created just before yet
+		tr, err := c.miniClient.MinitaskV1().TaskRuns(pr.Namespace).Get(c.ctx, child.TaskRunName, metav1.GetOptions{})
+		if err == nil {
+			byTask[child.PipelineTaskName] = tr
+			phases[child.PipelineTaskName] = tr.Status.Phase
+			results[child.PipelineTaskName] = tr.Status.Results
+			continue
+		}
+		if !apierrors.IsNotFound(err) {
+			return err
+		}
+
+		fmt.Println("Child TaskRun deleted:", child.TaskRunName)
+
+		deleted[child.PipelineTaskName] = child.TaskRunName
+		phases[child.PipelineTaskName] = "Failed"
+	}
+
+	failed, failure := "", ""
+	for _, pt := range spec.Tasks {
+		switch phases[pt.Name] {
+		case "Failed", "Cancelled":
+			if failed == "" {
+				failed = pt.Name
+				failure = fmt.Sprintf("task %q failed", pt.Name)
+				if trName, ok := deleted[pt.Name]; ok {
+					failure = fmt.Sprintf("task %q failed: TaskRun %s was deleted", pt.Name, trName)
+				}
+			}
+		}
+	}
+
+	// stop scheduling new tasks once one failed
+	if failed == "" {
+		for _, pt := range pipeline.Ready(spec, phases) {
+			tr := pipeline.MakeTaskRun(pr, pt, params, results)
+
+			fmt.Println("Creating TaskRun:", tr.Name)
+
+			created, err := c.miniClient.MinitaskV1().TaskRuns(pr.Namespace).Create(c.ctx, tr, metav1.CreateOptions{})
+			if err != nil {
+				if apierrors.IsAlreadyExists(err) {
+					fmt.Println("TaskRun already exists (race). Skipping.")
+					continue
+				}
+				return err
+			}
+
+			byTask[pt.Name] = created
+			phases[pt.Name] = created.Status.Phase
+		}
+	}
+
+	// aggregate status
+
+	prCopy := pr.DeepCopy()
+	prCopy.Status.ChildTaskRuns = nil
+
+	running, succeeded := 0, 0
+	for _, pt := range spec.Tasks {
+		if trName, ok := deleted[pt.Name]; ok {
+			prCopy.Status.ChildTaskRuns = append(prCopy.Status.ChildTaskRuns, miniv1.ChildTaskRunStatus{
+				PipelineTaskName: pt.Name,
+				TaskRunName:      trName,
+				Phase:            "Failed",
+			})
+			continue
+		}
+
+		tr, ok := byTask[pt.Name]
+		if !ok {
+			continue
+		}
+
+		prCopy.Status.ChildTaskRuns = append(prCopy.Status.ChildTaskRuns, miniv1.ChildTaskRunStatus{
+			PipelineTaskName: pt.Name,
+			TaskRunName:      tr.Name,
+			Phase:            tr.Status.Phase,
+		})
+
+		switch tr.Status.Phase {
+		case "Succeeded":
+			succeeded++
+		case "Failed", "Cancelled":
+		default:
+			running++
+		}
+	}
+
+	if prCopy.Status.StartTime == nil {
+		now := metav1.Now()
+		prCopy.Status.StartTime = &now
+	}
+
+	switch {
+
+	case failed != "" && running == 0:
+		pipeline.SetPhase(prCopy, "Failed", "TaskFailed", failure)
+		now := metav1.Now()
+		prCopy.Status.FinishTime = &now
+
+	case failed != "":
+		pipeline.SetPhase(prCopy, "Running", "TaskFailed",
+			fmt.Sprintf("%s, waiting for %d running tasks", failure, running))
+
+	case succeeded == len(spec.Tasks):
+		pipeline.SetPhase(prCopy, "Succeeded", "", "All tasks completed successfully")
+		now := metav1.Now()
+		prCopy.Status.FinishTime = &now
+
+	default:
+		pipeline.SetPhase(prCopy, "Running", "",
+			fmt.Sprintf("%d of %d tasks completed", succeeded, len(spec.Tasks)))
+	}
+
+	if equality.Semantic.DeepEqual(pr.Status, prCopy.Status) {
+		fmt.Println("No Status Change!")
+		return nil
+	}
+
+	if pr.Status.Phase != prCopy.Status.Phase {
+		fmt.Printf("Phase Transition %s -> %s\n", pr.Status.Phase, prCopy.Status.Phase)
+	}
+
+	_, err = c.miniClient.MinitaskV1().PipelineRuns(pr.Namespace).UpdateStatus(c.ctx, prCopy, metav1.UpdateOptions{})
+
+	return err
+}
+
+// failPipelineRun marks a PipelineRun that cannot run as Failed.
+func (c *Controller) failPipelineRun(pr *miniv1.PipelineRun, reason, message string) error {
+
+	fmt.Println("Marking PipelineRun as Failed:", message)
+
+	prCopy := pr.DeepCopy()
+	pipeline.SetPhase(prCopy, "Failed", reason, message)
+	now := metav1.Now()
+	prCopy.Status.FinishTime = &now
+
+	_, err := c.miniClient.MinitaskV1().PipelineRuns(pr.Namespace).UpdateStatus(c.ctx, prCopy, metav1.UpdateOptions{})
+
+	return err
+}
@@ -0,0 +1,25 @@
+package v1
+
+// concurrency -> group (may use $(params.name)), maxRunning, policy
+// set on the Task or the TaskRun; the TaskRun's own wins
+
+// ConcurrencyGroupPolicy says what happens to a run whose group is full
+type ConcurrencyGroupPolicy string
+
+const (
+	// ConcurrencyQueue keeps the run Queued until a slot frees up, first in first out
+	ConcurrencyQueue ConcurrencyGroupPolicy = "Queue"
+
+	// ConcurrencyReject fails the run
+	ConcurrencyReject ConcurrencyGroupPolicy = "Reject"
+
+	// ConcurrencyCancelInProgress cancels the running and queued runs of the
+	// group and starts the run once they stopped
+	ConcurrencyCancelInProgress ConcurrencyGroupPolicy = "CancelInProgress"
+)
+
+type Concurrency struct {
+	Group      string                 `json:"group"`
+	MaxRunning int32                  `json:"maxRunning,omitempty"` // 1 when unset
+	Policy     ConcurrencyGroupPolicy `json:"policy,omitempty"`     // Queue when unset
+}
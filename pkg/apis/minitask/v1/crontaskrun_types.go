@@ -0,0 +1,74 @@
+package v1
+
+// crontaskrun -> apiVersion, kind, metadata, spec, status
+// spec -> schedule, timeZone, taskRunTemplate, concurrencyPolicy, startingDeadlineSeconds,
+//   successfulRunsHistoryLimit, failedRunsHistoryLimit
+// taskRunTemplate -> metadata (labels, annotations), spec (a TaskRunSpec)
+// status -> LastScheduleTime, LastSuccessfulTime, Active
+// crontaskrunList -> for getting list of all crontaskruns
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ConcurrencyPolicy says what to do when a run is due while earlier ones are
+// still active
+type ConcurrencyPolicy string
+
+const (
+	// AllowConcurrent starts the new run next to the active ones
+	AllowConcurrent ConcurrencyPolicy = "Allow"
+
+	// ForbidConcurrent skips the new run while any run is active
+	ForbidConcurrent ConcurrencyPolicy = "Forbid"
+
+	// ReplaceConcurrent cancels the active runs and starts the new one
+	ReplaceConcurrent ConcurrencyPolicy = "Replace"
+)
+
+type CronTaskRunSpec struct {
+	// Schedule in standard cron format, e.g. "0 2 * * *"
+	Schedule string `json:"schedule"`
+
+	// TimeZone is an IANA name such as "Europe/Berlin", UTC when empty
+	TimeZone string `json:"timeZone,omitempty"`
+
+	TaskRunTemplate   TaskRunTemplateSpec `json:"taskRunTemplate"`
+	ConcurrencyPolicy ConcurrencyPolicy   `json:"concurrencyPolicy,omitempty"`
+
+	// StartingDeadlineSeconds skips runs that could not start within this
+	// many seconds of their scheduled time
+	StartingDeadlineSeconds *int64 `json:"startingDeadlineSeconds,omitempty"`
+
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+	FailedRunsHistoryLimit     *int32 `json:"failedRunsHistoryLimit,omitempty"`
+}
+
+// TaskRunTemplateSpec describes the TaskRuns created on schedule
+type TaskRunTemplateSpec struct {
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              TaskRunSpec `json:"spec"`
+}
+
+type CronTaskRunStatus struct {
+	LastScheduleTime   *metav1.Time             `json:"lastScheduleTime,omitempty"`
+	LastSuccessfulTime *metav1.Time             `json:"lastSuccessfulTime,omitempty"`
+	Active             []corev1.ObjectReference `json:"active,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CronTaskRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              CronTaskRunSpec   `json:"spec,omitempty"`
+	Status            CronTaskRunStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type CronTaskRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []CronTaskRun `json:"items"`
+}
@@ -0,0 +1,81 @@
+package v1
+
+// paramSpec -> declared by a Task (name, type, description, default)
+// param -> value supplied by a TaskRun (name, value)
+// paramValue -> string, array of strings or object of strings
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+type ParamType string
+
+const (
+	ParamTypeString ParamType = "string"
+	ParamTypeArray  ParamType = "array"
+	ParamTypeObject ParamType = "object"
+)
+
+type ParamSpec struct {
+	Name        string      `json:"name"`
+	Type        ParamType   `json:"type,omitempty"` // defaults to string
+	Description string      `json:"description,omitempty"`
+	Default     *ParamValue `json:"default,omitempty"`
+}
+
+type Param struct {
+	Name  string     `json:"name"`
+	Value ParamValue `json:"value"`
+}
+
+// ParamValue holds one of a string, an array or an object; in JSON it is
+// written as that plain value.
+type ParamValue struct {
+	Type      ParamType         `json:"-"`
+	StringVal string            `json:"-"`
+	ArrayVal  []string          `json:"-"`
+	ObjectVal map[string]string `json:"-"`
+}
+
+func (p ParamValue) MarshalJSON() ([]byte, error) {
+	switch p.Type {
+	case ParamTypeArray:
+		if p.ArrayVal == nil {
+			return []byte("[]"), nil
+		}
+		return json.Marshal(p.ArrayVal)
+	case ParamTypeObject:
+		if p.ObjectVal == nil {
+			return []byte("{}"), nil
+		}
+		return json.Marshal(p.ObjectVal)
+	default:
+		return json.Marshal(p.StringVal)
+	}
+}
+
+func (p *ParamValue) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if len(data) == 0 {
+		return fmt.Errorf("empty param value")
+	}
+
+	switch data[0] {
+	case '[':
+		p.Type = ParamTypeArray
+		return json.Unmarshal(data, &p.ArrayVal)
+	case '{':
+		p.Type = ParamTypeObject
+		return json.Unmarshal(data, &p.ObjectVal)
+	case '"':
+		p.Type = ParamTypeString
+		return json.Unmarshal(data, &p.StringVal)
+	}
+
+	// numbers and booleans are taken as their string form
+	p.Type = ParamTypeString
+	p.StringVal = string(data)
+	return nil
+}
@@ -0,0 +1,43 @@
+package v1
+
+// pipeline -> apiVersion, kind, metadata, spec
+// spec -> list of params, list of tasks
+// pipelineTask -> name, taskRef (+ kind) or taskSpec, params, runAfter, timeout, retries
+// params of a pipelineTask may use $(params.<name>) of the Pipeline and
+//   $(tasks.<task>.results.<result>) of an earlier task
+// pipelineList -> for getting list of all pipelines
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PipelineTask struct {
+	Name     string           `json:"name"`
+	TaskRef  string           `json:"taskRef,omitempty"`
+	Kind     string           `json:"kind,omitempty"`
+	TaskSpec *TaskSpec        `json:"taskSpec,omitempty"`
+	Params   []Param          `json:"params,omitempty"`
+	RunAfter []string         `json:"runAfter,omitempty"` // tasks that must succeed first
+	Timeout  *metav1.Duration `json:"timeout,omitempty"`
+	Retries  int              `json:"retries,omitempty"`
+}
+
+type PipelineSpec struct {
+	Params []ParamSpec    `json:"params,omitempty"`
+	Tasks  []PipelineTask `json:"tasks"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Pipeline struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              PipelineSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Pipeline `json:"items"`
+}
@@ -0,0 +1,52 @@
+package v1
+
+// pipelinerun -> apiVersion, kind, metadata, spec, status
+// spec -> pipelineRef, params
+// status -> Phase, StartTime, FinishTime, Conditions, ObservedGeneration, ChildTaskRuns
+// childTaskRun -> pipelineTaskName, taskRunName, phase
+// pipelinerunList -> for getting list of all pipelineruns
+
+import (
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type PipelineRunSpec struct {
+	PipelineRef string  `json:"pipelineRef"`
+	Params      []Param `json:"params,omitempty"`
+}
+
+// PipelineRunConditionSucceeded is True once every task succeeded, False
+// once one failed and Unknown while the run is in progress
+const PipelineRunConditionSucceeded = "Succeeded"
+
+type PipelineRunStatus struct {
+	Phase              string               `json:"phase,omitempty"`
+	StartTime          *metav1.Time         `json:"startTime,omitempty"`
+	FinishTime         *metav1.Time         `json:"finishTime,omitempty"`
+	Conditions         []metav1.Condition   `json:"conditions,omitempty"`
+	ObservedGeneration int64                `json:"observedGeneration,omitempty"`
+	ChildTaskRuns      []ChildTaskRunStatus `json:"childTaskRuns,omitempty"`
+}
+
+// ChildTaskRunStatus is the TaskRun created for one task of the Pipeline
+type ChildTaskRunStatus struct {
+	PipelineTaskName string `json:"pipelineTaskName"`
+	TaskRunName      string `json:"taskRunName"`
+	Phase            string `json:"phase,omitempty"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineRun struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              PipelineRunSpec   `json:"spec,omitempty"`
+	Status            PipelineRunStatus `json:"status,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type PipelineRunList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []PipelineRun `json:"items"`
+}
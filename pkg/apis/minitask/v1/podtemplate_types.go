@@ -0,0 +1,19 @@
+package v1
+
+// podTemplate -> scheduling and identity settings merged into the Pod of a
+//   TaskRun; set per TaskRun and/or as a namespace-wide default
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+type PodTemplate struct {
+	NodeSelector       map[string]string             `json:"nodeSelector,omitempty"`
+	Tolerations        []corev1.Toleration           `json:"tolerations,omitempty"`
+	Affinity           *corev1.Affinity              `json:"affinity,omitempty"`
+	ServiceAccountName string                        `json:"serviceAccountName,omitempty"`
+	ImagePullSecrets   []corev1.LocalObjectReference `json:"imagePullSecrets,omitempty"`
+	PriorityClassName  string                        `json:"priorityClassName,omitempty"`
+	SecurityContext    *corev1.PodSecurityContext    `json:"securityContext,omitempty"`
+	RuntimeClassName   *string                       `json:"runtimeClassName,omitempty"`
+}
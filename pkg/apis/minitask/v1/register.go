@@ -40,6 +40,16 @@ func addKnownTypes(scheme *runtime.Scheme) error {
 		&TaskList{},
 		&TaskRun{},
 		&TaskRunList{},
+		&ClusterTask{},
+		&ClusterTaskList{},
+		&Pipeline{},
+		&PipelineList{},
+		&PipelineRun{},
+		&PipelineRunList{},
+		&CronTaskRun{},
+		&CronTaskRunList{},
+		&Trigger{},
+		&TriggerList{},
 	)
 
 	// Register version in scheme
@@ -3,22 +3,54 @@ package v1
 // task -> apiVersion, kind, metadata, spec
 // TypeMeta -> apiVersion, kind
 // ObjectMeta -> metadata(name, labels, namespace)
-// spec -> list of steps
-// step -> name, image, script
+// spec -> list of params, list of workspaces, list of steps, list of results, timeout,
+//   successful/failed runs history limits, concurrency
+// result -> name, description (written by steps to $(results.<name>.path))
+// step -> name, image, script, env
 // taskList -> for getting list of all tasks
 
 import (
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
+// Step runs either a script (with /bin/sh, or the interpreter named by a
+// #! line) or the command and args, like a plain container.
 type Step struct {
-	Name   string `json:"name"`
-	Image  string `json:"image"`
-	Script string `json:"script"`
+	Name    string   `json:"name"`
+	Image   string   `json:"image"`
+	Script  string   `json:"script,omitempty"`
+	Command []string `json:"command,omitempty"`
+	Args    []string `json:"args,omitempty"`
+
+	Env             []corev1.EnvVar             `json:"env,omitempty"`
+	EnvFrom         []corev1.EnvFromSource      `json:"envFrom,omitempty"`
+	Resources       corev1.ResourceRequirements `json:"resources,omitempty"`
+	WorkingDir      string                      `json:"workingDir,omitempty"`
+	ImagePullPolicy corev1.PullPolicy           `json:"imagePullPolicy,omitempty"`
+	SecurityContext *corev1.SecurityContext     `json:"securityContext,omitempty"`
+	VolumeMounts    []corev1.VolumeMount        `json:"volumeMounts,omitempty"`
+}
+
+type TaskResult struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
 }
 
 type TaskSpec struct {
-	Steps []Step `json:"steps"`
+	Params     []ParamSpec            `json:"params,omitempty"`
+	Workspaces []WorkspaceDeclaration `json:"workspaces,omitempty"`
+	Volumes    []corev1.Volume        `json:"volumes,omitempty"` // for step volumeMounts
+	Sidecars   []corev1.Container     `json:"sidecars,omitempty"`
+	Steps      []Step                 `json:"steps"`
+	Results    []TaskResult           `json:"results,omitempty"`
+	Timeout    *metav1.Duration       `json:"timeout,omitempty"` // default for TaskRuns that set none
+
+	// finished TaskRuns of this Task to keep, all when unset
+	SuccessfulRunsHistoryLimit *int32 `json:"successfulRunsHistoryLimit,omitempty"`
+	FailedRunsHistoryLimit     *int32 `json:"failedRunsHistoryLimit,omitempty"`
+
+	Concurrency *Concurrency `json:"concurrency,omitempty"`
 }
 
 // +genclient
@@ -3,8 +3,11 @@ package v1
 // taskrun -> apiVersion, kind, metadata, spec, status
 // TypeMeta -> apiVersion, kind
 // ObjectMeta -> metadata(name, labels, namespace)
-// spec -> taskRef
-// status -> Phase, PodName, StartTime, FinishTime
+// spec -> taskRef (+ kind) or taskSpec, params, workspaces, timeout, retries, status (Cancelled), podTemplate,
+//   ttlSecondsAfterFinished, concurrency
+// status -> Phase, PodName, StartTime, FinishTime, Steps, Conditions, ObservedGeneration, Results, RetriesStatus,
+//   ConcurrencyGroup, TaskSpec (snapshot of the resolved spec the Pod runs)
+// stepState -> name, containerName, state, exitCode, reason, startTime, finishTime, logLocation
 // taskrunList -> for getting list of all taskruns
 
 import (
@@ -12,14 +15,78 @@ import (
 )
 
 type TaskRunSpec struct {
-	TaskRef string `json:"taskRef"`
+	TaskRef     string             `json:"taskRef,omitempty"`
+	Kind        string             `json:"kind,omitempty"`     // what taskRef names: Task (default) or ClusterTask
+	TaskSpec    *TaskSpec          `json:"taskSpec,omitempty"` // inline alternative to taskRef
+	Params      []Param            `json:"params,omitempty"`
+	Workspaces  []WorkspaceBinding `json:"workspaces,omitempty"`
+	Timeout     *metav1.Duration   `json:"timeout,omitempty"` // 0 disables the timeout
+	Retries     int                `json:"retries,omitempty"` // extra attempts after a failed Pod
+	Status      string             `json:"status,omitempty"`  // set to Cancelled to stop the run
+	PodTemplate *PodTemplate       `json:"podTemplate,omitempty"`
+
+	// TTLSecondsAfterFinished deletes the run and its Pod this long after it finished
+	TTLSecondsAfterFinished *int32 `json:"ttlSecondsAfterFinished,omitempty"`
+
+	Concurrency *Concurrency `json:"concurrency,omitempty"` // overrides the Task's
 }
 
+const (
+	TaskKind        = "Task"
+	ClusterTaskKind = "ClusterTask"
+)
+
+// TaskRunSpecStatusCancelled asks the controller to stop a TaskRun
+const TaskRunSpecStatusCancelled = "Cancelled"
+
+// TaskRunConditionSucceeded is True once the run succeeded, False once it
+// failed and Unknown while it is still pending or running
+const TaskRunConditionSucceeded = "Succeeded"
+
 type TaskRunStatus struct {
-	Phase      string       `json:"phase,omitempty"`
-	PodName    string       `json:"podName,omitempty"`
+	Phase              string             `json:"phase,omitempty"`
+	PodName            string             `json:"podName,omitempty"`
+	StartTime          *metav1.Time       `json:"startTime,omitempty"`
+	FinishTime         *metav1.Time       `json:"finishTime,omitempty"`
+	Steps              []StepState        `json:"steps,omitempty"`
+	Conditions         []metav1.Condition `json:"conditions,omitempty"`
+	ObservedGeneration int64              `json:"observedGeneration,omitempty"`
+	Results            []TaskRunResult    `json:"results,omitempty"`
+	RetriesStatus      []TaskRunAttempt   `json:"retriesStatus,omitempty"`
+	ConcurrencyGroup   string             `json:"concurrencyGroup,omitempty"` // resolved group while Queued or holding a slot
+
+	// TaskSpec is the spec the Pod was built from, with params substituted
+	// and the timeout in effect, recorded when the first Pod is created
+	TaskSpec *TaskSpec `json:"taskSpec,omitempty"`
+}
+
+// TaskRunAttempt records an earlier, failed attempt of a retried TaskRun
+type TaskRunAttempt struct {
+	PodName    string       `json:"podName"`
+	Phase      string       `json:"phase"`
+	Reason     string       `json:"reason,omitempty"`
+	Message    string       `json:"message,omitempty"`
 	StartTime  *metav1.Time `json:"startTime,omitempty"`
 	FinishTime *metav1.Time `json:"finishTime,omitempty"`
+	Steps      []StepState  `json:"steps,omitempty"` // final step states, with the archived logs of the attempt
+}
+
+// TaskRunResult is the value a step wrote for a declared Task result
+type TaskRunResult struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// StepState is the observed state of a single step container
+type StepState struct {
+	Name          string       `json:"name"`
+	ContainerName string       `json:"containerName"`
+	State         string       `json:"state,omitempty"` // Waiting, Running or Terminated
+	ExitCode      *int32       `json:"exitCode,omitempty"`
+	Reason        string       `json:"reason,omitempty"`
+	StartTime     *metav1.Time `json:"startTime,omitempty"`
+	FinishTime    *metav1.Time `json:"finishTime,omitempty"`
+	LogLocation   string       `json:"logLocation,omitempty"` // where the archived log of the step is kept
 }
 
 // +genclient
@@ -0,0 +1,44 @@
+package v1
+
+// trigger -> apiVersion, kind, metadata, spec
+// spec -> secret, params, taskRunTemplate
+// triggerParam -> name, value (a JSONPath template over the payload)
+// triggerList -> for getting list of all triggers
+
+import (
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+type TriggerSpec struct {
+	// Secret holds the HMAC key requests must be signed with; the server
+	// rejects every request to a Trigger without one
+	Secret *corev1.SecretKeySelector `json:"secret"`
+
+	// Params are added to the TaskRun, with values taken from the payload
+	Params []TriggerParam `json:"params,omitempty"`
+
+	TaskRunTemplate TaskRunTemplateSpec `json:"taskRunTemplate"`
+}
+
+// TriggerParam maps the JSON payload to a TaskRun param. Value is a JSONPath
+// template such as "{.head_commit.id}" or "refs/{.ref}".
+type TriggerParam struct {
+	Name  string `json:"name"`
+	Value string `json:"value"`
+}
+
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type Trigger struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+	Spec              TriggerSpec `json:"spec,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+type TriggerList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata,omitempty"`
+	Items           []Trigger `json:"items"`
+}
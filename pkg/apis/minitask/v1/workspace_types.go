@@ -0,0 +1,28 @@
+package v1
+
+// workspaceDeclaration -> declared by a Task (name, mountPath, readOnly, optional)
+// workspaceBinding -> supplied by a TaskRun, binds a declared workspace to
+//   exactly one volume source
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+type WorkspaceDeclaration struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MountPath   string `json:"mountPath,omitempty"` // defaults to /workspace/<name>
+	ReadOnly    bool   `json:"readOnly,omitempty"`
+	Optional    bool   `json:"optional,omitempty"`
+}
+
+type WorkspaceBinding struct {
+	Name    string `json:"name"`
+	SubPath string `json:"subPath,omitempty"`
+
+	EmptyDir              *corev1.EmptyDirVolumeSource              `json:"emptyDir,omitempty"`
+	PersistentVolumeClaim *corev1.PersistentVolumeClaimVolumeSource `json:"persistentVolumeClaim,omitempty"`
+	VolumeClaimTemplate   *corev1.PersistentVolumeClaim             `json:"volumeClaimTemplate,omitempty"`
+	ConfigMap             *corev1.ConfigMapVolumeSource             `json:"configMap,omitempty"`
+	Secret                *corev1.SecretVolumeSource                `json:"secret,omitempty"`
+}
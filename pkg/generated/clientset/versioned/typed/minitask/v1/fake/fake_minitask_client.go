@@ -22,6 +22,22 @@ type FakeMinitaskV1 struct {
 	*testing.Fake
 }
 
+func (c *FakeMinitaskV1) ClusterTasks() v1.ClusterTaskInterface {
+	return newFakeClusterTasks(c)
+}
+
+func (c *FakeMinitaskV1) CronTaskRuns(namespace string) v1.CronTaskRunInterface {
+	return newFakeCronTaskRuns(c, namespace)
+}
+
+func (c *FakeMinitaskV1) Pipelines(namespace string) v1.PipelineInterface {
+	return newFakePipelines(c, namespace)
+}
+
+func (c *FakeMinitaskV1) PipelineRuns(namespace string) v1.PipelineRunInterface {
+	return newFakePipelineRuns(c, namespace)
+}
+
 func (c *FakeMinitaskV1) Tasks(namespace string) v1.TaskInterface {
 	return newFakeTasks(c, namespace)
 }
@@ -30,6 +46,10 @@ func (c *FakeMinitaskV1) TaskRuns(namespace string) v1.TaskRunInterface {
 	return newFakeTaskRuns(c, namespace)
 }
 
+func (c *FakeMinitaskV1) Triggers(namespace string) v1.TriggerInterface {
+	return newFakeTriggers(c, namespace)
+}
+
 // RESTClient returns a RESTClient that is used to communicate
 // with API server by this client implementation.
 func (c *FakeMinitaskV1) RESTClient() rest.Interface {
@@ -12,6 +12,16 @@ You may obtain a copy of the License at
 
 package v1
 
+type ClusterTaskExpansion interface{}
+
+type CronTaskRunExpansion interface{}
+
+type PipelineExpansion interface{}
+
+type PipelineRunExpansion interface{}
+
 type TaskExpansion interface{}
 
 type TaskRunExpansion interface{}
+
+type TriggerExpansion interface{}
@@ -22,8 +22,13 @@ import (
 
 type MinitaskV1Interface interface {
 	RESTClient() rest.Interface
+	ClusterTasksGetter
+	CronTaskRunsGetter
+	PipelinesGetter
+	PipelineRunsGetter
 	TasksGetter
 	TaskRunsGetter
+	TriggersGetter
 }
 
 // MinitaskV1Client is used to interact with features provided by the minitask.myorg.dev group.
@@ -31,6 +36,22 @@ type MinitaskV1Client struct {
 	restClient rest.Interface
 }
 
+func (c *MinitaskV1Client) ClusterTasks() ClusterTaskInterface {
+	return newClusterTasks(c)
+}
+
+func (c *MinitaskV1Client) CronTaskRuns(namespace string) CronTaskRunInterface {
+	return newCronTaskRuns(c, namespace)
+}
+
+func (c *MinitaskV1Client) Pipelines(namespace string) PipelineInterface {
+	return newPipelines(c, namespace)
+}
+
+func (c *MinitaskV1Client) PipelineRuns(namespace string) PipelineRunInterface {
+	return newPipelineRuns(c, namespace)
+}
+
 func (c *MinitaskV1Client) Tasks(namespace string) TaskInterface {
 	return newTasks(c, namespace)
 }
@@ -39,6 +60,10 @@ func (c *MinitaskV1Client) TaskRuns(namespace string) TaskRunInterface {
 	return newTaskRuns(c, namespace)
 }
 
+func (c *MinitaskV1Client) Triggers(namespace string) TriggerInterface {
+	return newTriggers(c, namespace)
+}
+
 // NewForConfig creates a new MinitaskV1Client for the given config.
 // NewForConfig is equivalent to NewForConfigAndClient(c, httpClient),
 // where httpClient was generated with rest.HTTPClientFor(c).
@@ -47,10 +47,20 @@ func (f *genericInformer) Lister() cache.GenericLister {
 func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource) (GenericInformer, error) {
 	switch resource {
 	// Group=minitask.myorg.dev, Version=v1
+	case v1.SchemeGroupVersion.WithResource("clustertasks"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Minitask().V1().ClusterTasks().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("crontaskruns"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Minitask().V1().CronTaskRuns().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("pipelines"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Minitask().V1().Pipelines().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("pipelineruns"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Minitask().V1().PipelineRuns().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("tasks"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Minitask().V1().Tasks().Informer()}, nil
 	case v1.SchemeGroupVersion.WithResource("taskruns"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Minitask().V1().TaskRuns().Informer()}, nil
+	case v1.SchemeGroupVersion.WithResource("triggers"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Minitask().V1().Triggers().Informer()}, nil
 
 	}
 
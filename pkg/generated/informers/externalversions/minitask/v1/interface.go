@@ -18,10 +18,20 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// ClusterTasks returns a ClusterTaskInformer.
+	ClusterTasks() ClusterTaskInformer
+	// CronTaskRuns returns a CronTaskRunInformer.
+	CronTaskRuns() CronTaskRunInformer
+	// Pipelines returns a PipelineInformer.
+	Pipelines() PipelineInformer
+	// PipelineRuns returns a PipelineRunInformer.
+	PipelineRuns() PipelineRunInformer
 	// Tasks returns a TaskInformer.
 	Tasks() TaskInformer
 	// TaskRuns returns a TaskRunInformer.
 	TaskRuns() TaskRunInformer
+	// Triggers returns a TriggerInformer.
+	Triggers() TriggerInformer
 }
 
 type version struct {
@@ -35,6 +45,26 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// ClusterTasks returns a ClusterTaskInformer.
+func (v *version) ClusterTasks() ClusterTaskInformer {
+	return &clusterTaskInformer{factory: v.factory, tweakListOptions: v.tweakListOptions}
+}
+
+// CronTaskRuns returns a CronTaskRunInformer.
+func (v *version) CronTaskRuns() CronTaskRunInformer {
+	return &cronTaskRunInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// Pipelines returns a PipelineInformer.
+func (v *version) Pipelines() PipelineInformer {
+	return &pipelineInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
+// PipelineRuns returns a PipelineRunInformer.
+func (v *version) PipelineRuns() PipelineRunInformer {
+	return &pipelineRunInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // Tasks returns a TaskInformer.
 func (v *version) Tasks() TaskInformer {
 	return &taskInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
@@ -44,3 +74,8 @@ func (v *version) Tasks() TaskInformer {
 func (v *version) TaskRuns() TaskRunInformer {
 	return &taskRunInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
 }
+
+// Triggers returns a TriggerInformer.
+func (v *version) Triggers() TriggerInformer {
+	return &triggerInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
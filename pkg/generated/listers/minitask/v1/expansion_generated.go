@@ -12,6 +12,34 @@ You may obtain a copy of the License at
 
 package v1
 
+// ClusterTaskListerExpansion allows custom methods to be added to
+// ClusterTaskLister.
+type ClusterTaskListerExpansion interface{}
+
+// CronTaskRunListerExpansion allows custom methods to be added to
+// CronTaskRunLister.
+type CronTaskRunListerExpansion interface{}
+
+// CronTaskRunNamespaceListerExpansion allows custom methods to be added to
+// CronTaskRunNamespaceLister.
+type CronTaskRunNamespaceListerExpansion interface{}
+
+// PipelineListerExpansion allows custom methods to be added to
+// PipelineLister.
+type PipelineListerExpansion interface{}
+
+// PipelineNamespaceListerExpansion allows custom methods to be added to
+// PipelineNamespaceLister.
+type PipelineNamespaceListerExpansion interface{}
+
+// PipelineRunListerExpansion allows custom methods to be added to
+// PipelineRunLister.
+type PipelineRunListerExpansion interface{}
+
+// PipelineRunNamespaceListerExpansion allows custom methods to be added to
+// PipelineRunNamespaceLister.
+type PipelineRunNamespaceListerExpansion interface{}
+
 // TaskListerExpansion allows custom methods to be added to
 // TaskLister.
 type TaskListerExpansion interface{}
@@ -27,3 +55,11 @@ type TaskRunListerExpansion interface{}
 // TaskRunNamespaceListerExpansion allows custom methods to be added to
 // TaskRunNamespaceLister.
 type TaskRunNamespaceListerExpansion interface{}
+
+// TriggerListerExpansion allows custom methods to be added to
+// TriggerLister.
+type TriggerListerExpansion interface{}
+
+// TriggerNamespaceListerExpansion allows custom methods to be added to
+// TriggerNamespaceLister.
+type TriggerNamespaceListerExpansion interface{}
@@ -0,0 +1,102 @@
+package logs
+
+// configmap sink -> ConfigMap <taskrun>-logs in the namespace of the run,
+//   one <step>.log key per step and <step>.attempt-<n>.log per step of a
+//   failed attempt, owned by the TaskRun
+
+import (
+	"context"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes"
+)
+
+// MaxConfigMapLogSize bounds all logs of one run, below the 1MiB object
+// limit; it is split evenly between the steps of every attempt
+const MaxConfigMapLogSize = 900 * 1024
+
+// truncatedMarker replaces the start of a log that did not fit
+const truncatedMarker = "[log truncated]\n"
+
+// ConfigMapSink stores logs in a ConfigMap next to the TaskRun.
+type ConfigMapSink struct {
+	Client kubernetes.Interface
+}
+
+// ConfigMapName returns the name of the ConfigMap holding the logs of tr
+func ConfigMapName(tr *miniv1.TaskRun) string {
+	return tr.Name + "-logs"
+}
+
+func (s *ConfigMapSink) Store(ctx context.Context, tr *miniv1.TaskRun, step string, log []byte) (string, error) {
+
+	name := ConfigMapName(tr)
+	key := step + ".log"
+
+	cm, err := s.Client.CoreV1().ConfigMaps(tr.Namespace).Get(ctx, name, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		cm = &corev1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:      name,
+				Namespace: tr.Namespace,
+				Labels:    map[string]string{resources.TaskRunLabel: tr.Name},
+				OwnerReferences: []metav1.OwnerReference{
+					*metav1.NewControllerRef(
+						tr,
+						miniv1.SchemeGroupVersion.WithKind("TaskRun"),
+					),
+				},
+			},
+		}
+	} else if err != nil {
+		return "", err
+	}
+
+	if cm.Data == nil {
+		cm.Data = map[string]string{}
+	}
+
+	// each step gets an equal share, so a large log cannot crowd out the
+	// steps after it; keep the end of the log, where failures show
+	budget := max(stepLogBudget(tr)-len(key), len(truncatedMarker))
+	if len(log) > budget {
+		log = append([]byte(truncatedMarker), log[len(log)-(budget-len(truncatedMarker)):]...)
+	}
+
+	cm.Data[key] = string(log)
+
+	if cm.ResourceVersion == "" {
+		_, err = s.Client.CoreV1().ConfigMaps(tr.Namespace).Create(ctx, cm, metav1.CreateOptions{})
+	} else {
+		_, err = s.Client.CoreV1().ConfigMaps(tr.Namespace).Update(ctx, cm, metav1.UpdateOptions{})
+	}
+	if err != nil {
+		return "", err
+	}
+
+	return "configmap://" + tr.Namespace + "/" + name + "/" + key, nil
+}
+
+// stepLogBudget returns the bytes one step of one attempt of tr may use,
+// key included
+func stepLogBudget(tr *miniv1.TaskRun) int {
+	steps := len(tr.Status.Steps)
+	if steps == 0 {
+		steps = 1
+	}
+	return MaxConfigMapLogSize / (steps * (tr.Spec.Retries + 1))
+}
+
+func (s *ConfigMapSink) Delete(ctx context.Context, tr *miniv1.TaskRun) error {
+
+	err := s.Client.CoreV1().ConfigMaps(tr.Namespace).Delete(ctx, ConfigMapName(tr), metav1.DeleteOptions{})
+	if apierrors.IsNotFound(err) {
+		return nil
+	}
+
+	return err
+}
@@ -0,0 +1,39 @@
+package logs
+
+// file sink -> <dir>/<namespace>/<taskrun>/<step>.log
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+)
+
+// FileSink stores logs on the local filesystem of the controller.
+type FileSink struct {
+	Dir string
+}
+
+func (s *FileSink) runDir(tr *miniv1.TaskRun) string {
+	return filepath.Join(s.Dir, tr.Namespace, tr.Name)
+}
+
+func (s *FileSink) Store(ctx context.Context, tr *miniv1.TaskRun, step string, log []byte) (string, error) {
+
+	dir := s.runDir(tr)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return "", err
+	}
+
+	path := filepath.Join(dir, step+".log")
+	if err := os.WriteFile(path, log, 0o644); err != nil {
+		return "", err
+	}
+
+	return "file://" + path, nil
+}
+
+func (s *FileSink) Delete(ctx context.Context, tr *miniv1.TaskRun) error {
+	return os.RemoveAll(s.runDir(tr))
+}
@@ -0,0 +1,32 @@
+package logs
+
+// archive -> the log of every step that ran, copied from the Pod of the
+//   last attempt once the TaskRun finished or before a cancelled or
+//   timed-out run loses its Pod, and from the Pod of every failed attempt
+//   before it is retried
+// location -> recorded per step in status.steps[].logLocation, and in
+//   status.retriesStatus[].steps[].logLocation for failed attempts
+
+import (
+	"context"
+	"fmt"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+)
+
+// Sink stores archived step logs.
+type Sink interface {
+	// Store keeps the log of one step of tr and returns where it went.
+	// Storing the same step again replaces the earlier log.
+	Store(ctx context.Context, tr *miniv1.TaskRun, step string, log []byte) (location string, err error)
+
+	// Delete removes every log stored for tr.
+	Delete(ctx context.Context, tr *miniv1.TaskRun) error
+}
+
+// AttemptStep names the log of step in the given failed attempt, counted
+// from 1. Step names cannot contain a dot, so it never clashes with the
+// log of a step of the last attempt.
+func AttemptStep(step string, attempt int) string {
+	return fmt.Sprintf("%s.attempt-%d", step, attempt)
+}
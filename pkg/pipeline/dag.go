@@ -0,0 +1,173 @@
+package pipeline
+
+// dag -> the tasks of a Pipeline and their dependencies
+// dependency -> a task in runAfter, or a task whose results are used
+//   through $(tasks.<task>.results.<result>)
+// ready -> not started yet and every dependency succeeded
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	"k8s.io/apimachinery/pkg/util/validation"
+)
+
+var resultRef = regexp.MustCompile(`\$\(tasks\.([^.()]+)\.results\.([^.()]+)\)`)
+
+// Dependencies returns the names of the tasks pt has to wait for.
+func Dependencies(pt miniv1.PipelineTask) []string {
+
+	deps := map[string]bool{}
+	for _, name := range pt.RunAfter {
+		deps[name] = true
+	}
+
+	for _, param := range pt.Params {
+		for _, value := range paramStrings(param.Value) {
+			for _, match := range resultRef.FindAllStringSubmatch(value, -1) {
+				deps[match[1]] = true
+			}
+		}
+	}
+
+	var names []string
+	for name := range deps {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func paramStrings(value miniv1.ParamValue) []string {
+	switch value.Type {
+	case miniv1.ParamTypeArray:
+		return value.ArrayVal
+	case miniv1.ParamTypeObject:
+		var values []string
+		for _, v := range value.ObjectVal {
+			values = append(values, v)
+		}
+		return values
+	}
+	return []string{value.StringVal}
+}
+
+// Validate checks that task names are unique and usable in TaskRun names,
+// that every task has exactly one task source, and that dependencies name
+// existing tasks and form no cycle.
+func Validate(spec *miniv1.PipelineSpec) error {
+
+	if len(spec.Tasks) == 0 {
+		return &resources.ValidationError{Reason: "InvalidPipeline", Message: "Pipeline has no tasks"}
+	}
+
+	tasks := map[string]bool{}
+
+	for _, pt := range spec.Tasks {
+		if errs := validation.IsDNS1123Label(pt.Name); len(errs) > 0 {
+			return &resources.ValidationError{
+				Reason:  "InvalidPipeline",
+				Message: fmt.Sprintf("task name %q is invalid: %s", pt.Name, strings.Join(errs, "; ")),
+			}
+		}
+
+		if tasks[pt.Name] {
+			return &resources.ValidationError{
+				Reason:  "InvalidPipeline",
+				Message: fmt.Sprintf("task name %q is used more than once", pt.Name),
+			}
+		}
+		tasks[pt.Name] = true
+
+		if (pt.TaskRef == "") == (pt.TaskSpec == nil) {
+			return &resources.ValidationError{
+				Reason:  "InvalidPipeline",
+				Message: fmt.Sprintf("task %q must set exactly one of taskRef and taskSpec", pt.Name),
+			}
+		}
+	}
+
+	// Kahn's algorithm: repeatedly take the tasks whose dependencies are
+	// all taken; whatever is left over is part of a cycle
+	pending := map[string][]string{}
+	for _, pt := range spec.Tasks {
+		deps := Dependencies(pt)
+		for _, dep := range deps {
+			if !tasks[dep] {
+				return &resources.ValidationError{
+					Reason:  "InvalidPipeline",
+					Message: fmt.Sprintf("task %q depends on unknown task %q", pt.Name, dep),
+				}
+			}
+		}
+		pending[pt.Name] = deps
+	}
+
+	done := map[string]bool{}
+	for progress := true; progress; {
+		progress = false
+		for name, deps := range pending {
+			if allDone(deps, done) {
+				done[name] = true
+				delete(pending, name)
+				progress = true
+			}
+		}
+	}
+
+	if len(pending) > 0 {
+		var cycle []string
+		for name := range pending {
+			cycle = append(cycle, name)
+		}
+		sort.Strings(cycle)
+
+		return &resources.ValidationError{
+			Reason:  "InvalidPipeline",
+			Message: "tasks depend on each other in a cycle: " + strings.Join(cycle, ", "),
+		}
+	}
+
+	return nil
+}
+
+func allDone(deps []string, done map[string]bool) bool {
+	for _, dep := range deps {
+		if !done[dep] {
+			return false
+		}
+	}
+	return true
+}
+
+// Ready returns, in declared order, the tasks that can start now. phases
+// maps the name of every started task to the phase of its TaskRun.
+func Ready(spec *miniv1.PipelineSpec, phases map[string]string) []miniv1.PipelineTask {
+
+	var ready []miniv1.PipelineTask
+
+	for _, pt := range spec.Tasks {
+		if _, started := phases[pt.Name]; started {
+			continue
+		}
+
+		succeeded := true
+		for _, dep := range Dependencies(pt) {
+			if phases[dep] != "Succeeded" {
+				succeeded = false
+				break
+			}
+		}
+
+		if succeeded {
+			ready = append(ready, pt)
+		}
+	}
+
+	return ready
+}
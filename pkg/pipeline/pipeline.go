@@ -0,0 +1,128 @@
+package pipeline
+
+// child taskrun -> one TaskRun per Pipeline task, named <pipelinerun>-<task>,
+//   labelled with both and owned by the PipelineRun
+
+import (
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// PipelineRunLabel is set on child TaskRuns to the owning PipelineRun
+	PipelineRunLabel = "minitask.myorg.dev/pipelineRun"
+
+	// PipelineTaskLabel is set on child TaskRuns to their Pipeline task
+	PipelineTaskLabel = "minitask.myorg.dev/pipelineTask"
+)
+
+// TaskRunName returns the name of the child TaskRun for a Pipeline task
+func TaskRunName(pr *miniv1.PipelineRun, taskName string) string {
+	return pr.Name + "-" + taskName
+}
+
+// MakeTaskRun builds the child TaskRun for pt. params are the resolved
+// Pipeline params and results holds the results of finished tasks by task
+// name; both are substituted into the param values of pt.
+func MakeTaskRun(pr *miniv1.PipelineRun, pt miniv1.PipelineTask, params map[string]miniv1.ParamValue, results map[string][]miniv1.TaskRunResult) *miniv1.TaskRun {
+
+	oldnew := resources.ParamReplacements(params)
+	for task, taskResults := range results {
+		for _, result := range taskResults {
+			oldnew = append(oldnew, "$(tasks."+task+".results."+result.Name+")", result.Value)
+		}
+	}
+	replacer := strings.NewReplacer(oldnew...)
+
+	var trParams []miniv1.Param
+
+	for _, param := range pt.Params {
+		value := *param.Value.DeepCopy()
+
+		switch value.Type {
+
+		case miniv1.ParamTypeArray:
+			var items []string
+			for _, item := range value.ArrayVal {
+				if array, ok := resources.ArrayParam(item, params); ok {
+					items = append(items, array...)
+				} else {
+					items = append(items, replacer.Replace(item))
+				}
+			}
+			value.ArrayVal = items
+
+		case miniv1.ParamTypeObject:
+			for key, item := range value.ObjectVal {
+				value.ObjectVal[key] = replacer.Replace(item)
+			}
+
+		default:
+			// a whole $(params.name[*]) passes an array param on as is
+			if array, ok := resources.ArrayParam(value.StringVal, params); ok {
+				value = miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: array}
+			} else {
+				value.StringVal = replacer.Replace(value.StringVal)
+			}
+		}
+
+		trParams = append(trParams, miniv1.Param{Name: param.Name, Value: value})
+	}
+
+	return &miniv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      TaskRunName(pr, pt.Name),
+			Namespace: pr.Namespace,
+			Labels: map[string]string{
+				PipelineRunLabel:  pr.Name,
+				PipelineTaskLabel: pt.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					pr,
+					miniv1.SchemeGroupVersion.WithKind("PipelineRun"),
+				),
+			},
+		},
+		Spec: miniv1.TaskRunSpec{
+			TaskRef:  pt.TaskRef,
+			Kind:     pt.Kind,
+			TaskSpec: pt.TaskSpec.DeepCopy(),
+			Params:   trParams,
+			Timeout:  pt.Timeout.DeepCopy(),
+			Retries:  pt.Retries,
+		},
+	}
+}
+
+// SetPhase moves pr to phase and keeps the Succeeded condition and
+// observedGeneration in step with it, like resources.SetPhase for TaskRuns.
+func SetPhase(pr *miniv1.PipelineRun, phase, reason, message string) {
+
+	status := metav1.ConditionUnknown
+	switch phase {
+	case "Succeeded":
+		status = metav1.ConditionTrue
+	case "Failed", "Cancelled":
+		status = metav1.ConditionFalse
+	}
+
+	if reason == "" {
+		reason = phase
+	}
+
+	pr.Status.Phase = phase
+	pr.Status.ObservedGeneration = pr.Generation
+
+	meta.SetStatusCondition(&pr.Status.Conditions, metav1.Condition{
+		Type:               miniv1.PipelineRunConditionSucceeded,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: pr.Generation,
+	})
+}
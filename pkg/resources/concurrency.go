@@ -0,0 +1,108 @@
+package resources
+
+// concurrency group -> group after param substitution, scoped to the
+//   namespace and recorded in status.concurrencyGroup once a run is Queued
+//   or started
+// slot -> held by a run of the group that is Pending or Running and not
+//   being deleted
+// queue -> Queued runs of the group, oldest first
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+)
+
+// ResolveConcurrency returns the concurrency settings of tr, its own or
+// else those of spec, with $(params...) substituted into the group and
+// defaults applied. It returns nil when neither sets any.
+func ResolveConcurrency(tr *miniv1.TaskRun, spec *miniv1.TaskSpec) (*miniv1.Concurrency, error) {
+
+	concurrency := tr.Spec.Concurrency
+	if concurrency == nil {
+		concurrency = spec.Concurrency
+	}
+	if concurrency == nil {
+		return nil, nil
+	}
+
+	values, err := ResolveParams(spec.Params, tr.Spec.Params)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := concurrency.DeepCopy()
+	resolved.Group = strings.NewReplacer(ParamReplacements(values)...).Replace(resolved.Group)
+
+	if resolved.Group == "" {
+		return nil, &ValidationError{
+			Reason:  "InvalidConcurrency",
+			Message: "concurrency group must not be empty",
+		}
+	}
+
+	if resolved.MaxRunning < 0 {
+		return nil, &ValidationError{
+			Reason:  "InvalidConcurrency",
+			Message: fmt.Sprintf("maxRunning must not be negative, got %d", resolved.MaxRunning),
+		}
+	}
+	if resolved.MaxRunning == 0 {
+		resolved.MaxRunning = 1
+	}
+
+	switch resolved.Policy {
+	case "":
+		resolved.Policy = miniv1.ConcurrencyQueue
+	case miniv1.ConcurrencyQueue, miniv1.ConcurrencyReject, miniv1.ConcurrencyCancelInProgress:
+	default:
+		return nil, &ValidationError{
+			Reason: "InvalidConcurrency",
+			Message: fmt.Sprintf("policy must be %s, %s or %s, got %q",
+				miniv1.ConcurrencyQueue, miniv1.ConcurrencyReject, miniv1.ConcurrencyCancelInProgress, resolved.Policy),
+		}
+	}
+
+	return resolved, nil
+}
+
+// Admit decides whether tr may start now. members are the other TaskRuns
+// recorded in the same group. tr starts when a slot is free and no Queued
+// run is ahead of it. running are the members holding a slot and ahead the
+// Queued members created before tr.
+func Admit(concurrency *miniv1.Concurrency, tr *miniv1.TaskRun, members []*miniv1.TaskRun) (admit bool, running, ahead []*miniv1.TaskRun) {
+
+	for _, member := range members {
+		// a run being deleted gives up its slot and its place in the queue
+		if member.DeletionTimestamp != nil {
+			continue
+		}
+
+		switch member.Status.Phase {
+		case "Pending", "Running":
+			running = append(running, member)
+		case "Queued":
+			if queuedBefore(member, tr) {
+				ahead = append(ahead, member)
+			}
+		}
+	}
+
+	sort.Slice(ahead, func(i, j int) bool {
+		return queuedBefore(ahead[i], ahead[j])
+	})
+
+	admit = len(running)+len(ahead) < int(concurrency.MaxRunning)
+
+	return admit, running, ahead
+}
+
+// queuedBefore orders runs by creation, then by name
+func queuedBefore(a, b *miniv1.TaskRun) bool {
+	if !a.CreationTimestamp.Equal(&b.CreationTimestamp) {
+		return a.CreationTimestamp.Before(&b.CreationTimestamp)
+	}
+	return a.Name < b.Name
+}
@@ -0,0 +1,12 @@
+package resources
+
+// ValidationError is returned for TaskRuns that can never run as specified.
+// Controllers mark such runs Failed with Reason instead of retrying them.
+type ValidationError struct {
+	Reason  string
+	Message string
+}
+
+func (e *ValidationError) Error() string {
+	return e.Reason + ": " + e.Message
+}
@@ -0,0 +1,23 @@
+package resources
+
+import (
+	"slices"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+)
+
+// TaskRunFinalizer keeps a deleted TaskRun around until the controller has
+// run its cleanup hooks
+const TaskRunFinalizer = "minitask.myorg.dev/cleanup"
+
+// HasFinalizer reports whether tr carries TaskRunFinalizer
+func HasFinalizer(tr *miniv1.TaskRun) bool {
+	return slices.Contains(tr.Finalizers, TaskRunFinalizer)
+}
+
+// RemoveFinalizer returns the finalizers of tr without TaskRunFinalizer
+func RemoveFinalizer(tr *miniv1.TaskRun) []string {
+	return slices.DeleteFunc(slices.Clone(tr.Finalizers), func(f string) bool {
+		return f == TaskRunFinalizer
+	})
+}
@@ -0,0 +1,215 @@
+package resources
+
+// params -> declared by the Task, supplied by the TaskRun
+// $(params.name) -> string value
+// $(params.name[*]) -> all array items joined by spaces (one arg per item
+//   when it is a whole command/args item), $(params.name[0]) -> one item
+// $(params.name.key) -> one key of an object value
+// $(results.name.path) -> file a step writes a declared result to
+// $(workspaces.name.path) -> mount path of a declared workspace
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+)
+
+// ApplyParams resolves the TaskRun params against the params declared by
+// spec and returns a copy of spec with every $(params...),
+// $(results...path) and $(workspaces...path) reference in the step scripts,
+// images, command, args, working dirs and env values replaced. Sidecar
+// images, command, args and env values and the concurrency group are
+// substituted too.
+func ApplyParams(spec *miniv1.TaskSpec, params []miniv1.Param) (*miniv1.TaskSpec, error) {
+
+	values, err := ResolveParams(spec.Params, params)
+	if err != nil {
+		return nil, err
+	}
+
+	replacer := paramReplacer(values, spec)
+
+	resolved := spec.DeepCopy()
+	for i := range resolved.Steps {
+		step := &resolved.Steps[i]
+		step.Script = replacer.Replace(step.Script)
+		step.Image = replacer.Replace(step.Image)
+		step.WorkingDir = replacer.Replace(step.WorkingDir)
+		step.Command = replaceArgs(step.Command, values, replacer)
+		step.Args = replaceArgs(step.Args, values, replacer)
+		for j := range step.Env {
+			step.Env[j].Value = replacer.Replace(step.Env[j].Value)
+		}
+	}
+
+	if resolved.Concurrency != nil {
+		resolved.Concurrency.Group = replacer.Replace(resolved.Concurrency.Group)
+	}
+
+	for i := range resolved.Sidecars {
+		sidecar := &resolved.Sidecars[i]
+		sidecar.Image = replacer.Replace(sidecar.Image)
+		sidecar.Command = replaceArgs(sidecar.Command, values, replacer)
+		sidecar.Args = replaceArgs(sidecar.Args, values, replacer)
+		for j := range sidecar.Env {
+			sidecar.Env[j].Value = replacer.Replace(sidecar.Env[j].Value)
+		}
+	}
+
+	return resolved, nil
+}
+
+// replaceArgs substitutes params in a command or args list. An item that
+// is exactly $(params.name[*]) expands into one item per array element.
+func replaceArgs(args []string, values map[string]miniv1.ParamValue, replacer *strings.Replacer) []string {
+
+	if args == nil {
+		return nil
+	}
+
+	out := []string{}
+
+	for _, arg := range args {
+		if items, ok := ArrayParam(arg, values); ok {
+			out = append(out, items...)
+			continue
+		}
+		out = append(out, replacer.Replace(arg))
+	}
+
+	return out
+}
+
+// ArrayParam returns the items of the array param item refers to, when
+// item is exactly a $(params.name[*]) reference.
+func ArrayParam(item string, values map[string]miniv1.ParamValue) ([]string, bool) {
+
+	if !strings.HasPrefix(item, "$(params.") || !strings.HasSuffix(item, "[*])") {
+		return nil, false
+	}
+
+	name := strings.TrimSuffix(strings.TrimPrefix(item, "$(params."), "[*])")
+	value, ok := values[name]
+	if !ok || value.Type != miniv1.ParamTypeArray {
+		return nil, false
+	}
+
+	return append([]string{}, value.ArrayVal...), true
+}
+
+// ResolveParams merges supplied values with declared defaults and checks
+// that every declared param ends up with a value of the declared type.
+func ResolveParams(declared []miniv1.ParamSpec, supplied []miniv1.Param) (map[string]miniv1.ParamValue, error) {
+
+	given := map[string]miniv1.ParamValue{}
+	for _, p := range supplied {
+		given[p.Name] = p.Value
+	}
+
+	values := map[string]miniv1.ParamValue{}
+	var missing []string
+
+	for _, ps := range declared {
+		paramType := ps.Type
+		if paramType == "" {
+			paramType = miniv1.ParamTypeString
+		}
+
+		value, ok := given[ps.Name]
+		delete(given, ps.Name)
+
+		if !ok {
+			if ps.Default == nil {
+				missing = append(missing, ps.Name)
+				continue
+			}
+			value = *ps.Default
+		}
+
+		valueType := value.Type
+		if valueType == "" {
+			valueType = miniv1.ParamTypeString
+		}
+
+		if valueType != paramType {
+			return nil, &ValidationError{
+				Reason:  "InvalidParams",
+				Message: fmt.Sprintf("param %q must be of type %s, got %s", ps.Name, paramType, valueType),
+			}
+		}
+
+		values[ps.Name] = value
+	}
+
+	if len(missing) > 0 {
+		return nil, &ValidationError{
+			Reason:  "MissingParams",
+			Message: "missing values for required params: " + strings.Join(missing, ", "),
+		}
+	}
+
+	if len(given) > 0 {
+		var unknown []string
+		for name := range given {
+			unknown = append(unknown, name)
+		}
+		sort.Strings(unknown)
+
+		return nil, &ValidationError{
+			Reason:  "InvalidParams",
+			Message: "params not declared by the Task: " + strings.Join(unknown, ", "),
+		}
+	}
+
+	return values, nil
+}
+
+func paramReplacer(values map[string]miniv1.ParamValue, spec *miniv1.TaskSpec) *strings.Replacer {
+
+	var oldnew []string
+
+	for _, result := range spec.Results {
+		oldnew = append(oldnew, "$(results."+result.Name+".path)", ResultPath(result.Name))
+	}
+
+	for _, ws := range spec.Workspaces {
+		oldnew = append(oldnew, "$(workspaces."+ws.Name+".path)", WorkspacePath(ws))
+	}
+
+	oldnew = append(oldnew, ParamReplacements(values)...)
+
+	return strings.NewReplacer(oldnew...)
+}
+
+// ParamReplacements returns the old, new pairs for strings.NewReplacer that
+// substitute every $(params...) reference to values.
+func ParamReplacements(values map[string]miniv1.ParamValue) []string {
+
+	var oldnew []string
+
+	for name, value := range values {
+		prefix := "$(params." + name
+
+		switch value.Type {
+
+		case miniv1.ParamTypeArray:
+			oldnew = append(oldnew, prefix+"[*])", strings.Join(value.ArrayVal, " "))
+			for i, item := range value.ArrayVal {
+				oldnew = append(oldnew, prefix+"["+strconv.Itoa(i)+"])", item)
+			}
+
+		case miniv1.ParamTypeObject:
+			for key, item := range value.ObjectVal {
+				oldnew = append(oldnew, prefix+"."+key+")", item)
+			}
+
+		default:
+			oldnew = append(oldnew, prefix+")", value.StringVal)
+		}
+	}
+
+	return oldnew
+}
@@ -0,0 +1,116 @@
+package resources
+
+// pod -> built from a TaskRun and the TaskSpec it resolves to
+// steps -> run one after another, in declared order
+// step 1..n-1 -> init containers (each must exit 0 before the next starts)
+// step n -> regular container, so the Pod completes when it exits
+// workspaces -> bound volumes mounted into every step
+// results -> when declared, every step is an init container and the
+//   results collector is the regular container
+// sidecars -> native sidecars started before the first step, see sidecars.go
+// shebang scripts -> written by a prepare-scripts init container first
+// podTemplate -> scheduling and identity settings, see podtemplate.go
+
+import (
+	"fmt"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TaskRunLabel is set on every Pod to the name of the owning TaskRun
+const TaskRunLabel = "minitask"
+
+const stepPrefix = "step-"
+
+// StepContainerName returns the container name used for a step
+func StepContainerName(stepName string) string {
+	return stepPrefix + stepName
+}
+
+// PodName returns the name of the Pod for the current attempt of a
+// TaskRun; retried attempts get a distinct name per attempt
+func PodName(tr *miniv1.TaskRun) string {
+	if attempt := len(tr.Status.RetriesStatus); attempt > 0 {
+		return fmt.Sprintf("%s-pod-retry%d", tr.Name, attempt)
+	}
+	return tr.Name + "-pod"
+}
+
+// MakePod builds the Pod that executes the steps of spec for tr. spec is
+// expected to have its params applied and its steps and workspaces
+// validated already, see ApplyParams, ValidateTask and ValidateWorkspaces.
+// The TaskRun's podTemplate is merged over defaults, the namespace default
+// template (may be nil).
+//
+// Init containers run sequentially and the Pod fails as soon as one of them
+// exits non-zero (RestartPolicy Never), so the remaining steps are skipped.
+func MakePod(tr *miniv1.TaskRun, spec *miniv1.TaskSpec, defaults *miniv1.PodTemplate) *corev1.Pod {
+
+	volumes, workspaceMounts := workspaceVolumes(tr, spec)
+	volumes = append(volumes, spec.Volumes...)
+
+	var steps []corev1.Container
+	shebang := false
+
+	for _, step := range spec.Steps {
+		container := stepContainer(step)
+		shebang = shebang || hasShebang(step)
+		container.VolumeMounts = append(container.VolumeMounts, workspaceMounts...)
+		if len(spec.Results) > 0 {
+			container.VolumeMounts = append(container.VolumeMounts, resultsVolumeMount())
+		}
+		steps = append(steps, container)
+	}
+
+	var initContainers, containers []corev1.Container
+
+	if len(spec.Results) > 0 {
+		initContainers = steps
+		containers = []corev1.Container{resultsContainer(spec.Results)}
+		volumes = append(volumes, resultsVolume())
+	} else if len(steps) > 0 {
+		initContainers = steps[:len(steps)-1]
+		containers = steps[len(steps)-1:]
+	}
+
+	var sidecars []corev1.Container
+	for _, sidecar := range spec.Sidecars {
+		container := sidecarContainer(sidecar)
+		container.VolumeMounts = append(container.VolumeMounts, workspaceMounts...)
+		sidecars = append(sidecars, container)
+	}
+	initContainers = append(sidecars, initContainers...)
+
+	if shebang {
+		initContainers = append([]corev1.Container{prepareScriptsContainer(spec.Steps)}, initContainers...)
+		volumes = append(volumes, scriptsVolume())
+	}
+
+	pod := &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:      PodName(tr),
+			Namespace: tr.Namespace,
+			Labels: map[string]string{
+				TaskRunLabel: tr.Name,
+			},
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					tr,
+					miniv1.SchemeGroupVersion.WithKind("TaskRun"),
+				),
+			},
+		},
+		Spec: corev1.PodSpec{
+			RestartPolicy:  corev1.RestartPolicyNever,
+			InitContainers: initContainers,
+			Containers:     containers,
+			Volumes:        volumes,
+		},
+	}
+
+	applyPodTemplate(&pod.Spec, mergePodTemplates(defaults, tr.Spec.PodTemplate))
+
+	return pod
+}
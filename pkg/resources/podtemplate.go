@@ -0,0 +1,92 @@
+package resources
+
+// podTemplate -> namespace default (ConfigMap) merged with the TaskRun's
+//   own podTemplate; fields set on the TaskRun win
+
+import (
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/yaml"
+)
+
+const (
+	// DefaultsConfigMapName is the per-namespace ConfigMap holding defaults
+	DefaultsConfigMapName = "minitask-defaults"
+
+	// DefaultPodTemplateKey holds the default podTemplate, as YAML
+	DefaultPodTemplateKey = "podTemplate"
+)
+
+// ParsePodTemplate reads the default pod template from the defaults
+// ConfigMap. It returns nil when the ConfigMap sets none.
+func ParsePodTemplate(cm *corev1.ConfigMap) (*miniv1.PodTemplate, error) {
+
+	data, ok := cm.Data[DefaultPodTemplateKey]
+	if !ok {
+		return nil, nil
+	}
+
+	tmpl := &miniv1.PodTemplate{}
+	if err := yaml.UnmarshalStrict([]byte(data), tmpl); err != nil {
+		return nil, &ValidationError{
+			Reason:  "InvalidPodTemplate",
+			Message: "ConfigMap " + cm.Name + " has an invalid " + DefaultPodTemplateKey + ": " + err.Error(),
+		}
+	}
+
+	return tmpl, nil
+}
+
+// mergePodTemplates returns defaults overridden by every field tmpl sets.
+// Either may be nil.
+func mergePodTemplates(defaults, tmpl *miniv1.PodTemplate) *miniv1.PodTemplate {
+
+	merged := &miniv1.PodTemplate{}
+	if defaults != nil {
+		merged = defaults.DeepCopy()
+	}
+
+	if tmpl == nil {
+		return merged
+	}
+
+	tmpl = tmpl.DeepCopy()
+
+	if tmpl.NodeSelector != nil {
+		merged.NodeSelector = tmpl.NodeSelector
+	}
+	if tmpl.Tolerations != nil {
+		merged.Tolerations = tmpl.Tolerations
+	}
+	if tmpl.Affinity != nil {
+		merged.Affinity = tmpl.Affinity
+	}
+	if tmpl.ServiceAccountName != "" {
+		merged.ServiceAccountName = tmpl.ServiceAccountName
+	}
+	if tmpl.ImagePullSecrets != nil {
+		merged.ImagePullSecrets = tmpl.ImagePullSecrets
+	}
+	if tmpl.PriorityClassName != "" {
+		merged.PriorityClassName = tmpl.PriorityClassName
+	}
+	if tmpl.SecurityContext != nil {
+		merged.SecurityContext = tmpl.SecurityContext
+	}
+	if tmpl.RuntimeClassName != nil {
+		merged.RuntimeClassName = tmpl.RuntimeClassName
+	}
+
+	return merged
+}
+
+func applyPodTemplate(spec *corev1.PodSpec, tmpl *miniv1.PodTemplate) {
+	spec.NodeSelector = tmpl.NodeSelector
+	spec.Tolerations = tmpl.Tolerations
+	spec.Affinity = tmpl.Affinity
+	spec.ServiceAccountName = tmpl.ServiceAccountName
+	spec.ImagePullSecrets = tmpl.ImagePullSecrets
+	spec.PriorityClassName = tmpl.PriorityClassName
+	spec.SecurityContext = tmpl.SecurityContext
+	spec.RuntimeClassName = tmpl.RuntimeClassName
+}
@@ -0,0 +1,88 @@
+package resources
+
+// retention -> ttlSecondsAfterFinished on the TaskRun, counted from FinishTime
+// history -> successfulRunsHistoryLimit / failedRunsHistoryLimit on the Task,
+//   counted over the finished TaskRuns referencing it; unset keeps all
+
+import (
+	"sort"
+	"time"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+)
+
+// Finished reports whether tr reached a terminal phase
+func Finished(tr *miniv1.TaskRun) bool {
+	switch tr.Status.Phase {
+	case "Succeeded", "Failed", "Cancelled":
+		return true
+	}
+	return false
+}
+
+// TTLRemaining returns how long a finished tr is kept before it expires.
+// ok is false when tr is not finished or sets no ttlSecondsAfterFinished.
+func TTLRemaining(tr *miniv1.TaskRun, now time.Time) (remaining time.Duration, ok bool) {
+
+	if tr.Spec.TTLSecondsAfterFinished == nil || !Finished(tr) || tr.Status.FinishTime == nil {
+		return 0, false
+	}
+
+	ttl := time.Duration(*tr.Spec.TTLSecondsAfterFinished) * time.Second
+
+	return tr.Status.FinishTime.Add(ttl).Sub(now), true
+}
+
+// SameTask reports whether two TaskRuns reference the same Task or
+// ClusterTask. Inline taskSpecs never match.
+func SameTask(a, b *miniv1.TaskRun) bool {
+	if a.Spec.TaskRef == "" || a.Spec.TaskRef != b.Spec.TaskRef {
+		return false
+	}
+	return a.Spec.Kind == b.Spec.Kind ||
+		(a.Spec.Kind == "" && b.Spec.Kind == miniv1.TaskKind) ||
+		(a.Spec.Kind == miniv1.TaskKind && b.Spec.Kind == "")
+}
+
+// OverHistoryLimit returns the finished runs beyond the history limits of
+// spec, oldest first. runs are the TaskRuns of one Task.
+func OverHistoryLimit(spec *miniv1.TaskSpec, runs []*miniv1.TaskRun) []*miniv1.TaskRun {
+
+	var succeeded, failed []*miniv1.TaskRun
+
+	for _, tr := range runs {
+		switch tr.Status.Phase {
+		case "Succeeded":
+			succeeded = append(succeeded, tr)
+		case "Failed", "Cancelled":
+			failed = append(failed, tr)
+		}
+	}
+
+	var over []*miniv1.TaskRun
+	over = append(over, oldest(succeeded, spec.SuccessfulRunsHistoryLimit)...)
+	over = append(over, oldest(failed, spec.FailedRunsHistoryLimit)...)
+
+	return over
+}
+
+// oldest returns all but the newest limit runs
+func oldest(runs []*miniv1.TaskRun, limit *int32) []*miniv1.TaskRun {
+
+	if limit == nil || len(runs) <= int(*limit) {
+		return nil
+	}
+
+	sort.Slice(runs, func(i, j int) bool {
+		return finishedAt(runs[i]).Before(finishedAt(runs[j]))
+	})
+
+	return runs[:len(runs)-int(*limit)]
+}
+
+func finishedAt(tr *miniv1.TaskRun) time.Time {
+	if tr.Status.FinishTime != nil {
+		return tr.Status.FinishTime.Time
+	}
+	return tr.CreationTimestamp.Time
+}
@@ -0,0 +1,177 @@
+package resources
+
+// results -> steps write each declared result to $(results.<name>.path)
+// collector -> last container of the Pod, runs after every step succeeded
+//   and reports the result files through its termination message
+// message -> one line per result: "<name> value <base64>", "<name> missing"
+//   or "<name> toolarge", followed by a final "end" line
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/utils/ptr"
+)
+
+const (
+	// ResultsDir is where steps write their results, one file per result
+	ResultsDir = "/minitask/results"
+
+	// ResultsContainerName is the container that collects the results
+	ResultsContainerName = "results"
+
+	// MaxResultSize is the largest value, in bytes, a single result may hold
+	MaxResultSize = 1024
+
+	resultsVolumeName = "minitask-results"
+)
+
+// HelperImage runs the helper containers (script preparation, results
+// collection); it needs a shell and base64
+var HelperImage = "busybox:1.36"
+
+// HelperUser is the uid the helper containers run as. They only write to
+// emptyDir volumes and the termination log, so they need no root, and a
+// podTemplate with runAsNonRoot: true does not keep them from starting.
+const HelperUser = 65532
+
+func helperSecurityContext() *corev1.SecurityContext {
+	return &corev1.SecurityContext{
+		RunAsUser:                ptr.To[int64](HelperUser),
+		RunAsGroup:               ptr.To[int64](HelperUser),
+		RunAsNonRoot:             ptr.To(true),
+		AllowPrivilegeEscalation: ptr.To(false),
+		Capabilities: &corev1.Capabilities{
+			Drop: []corev1.Capability{"ALL"},
+		},
+	}
+}
+
+// ResultPath returns the file a step writes the named result to
+func ResultPath(name string) string {
+	return ResultsDir + "/" + name
+}
+
+func resultsVolumeMount() corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      resultsVolumeName,
+		MountPath: ResultsDir,
+	}
+}
+
+func resultsVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: resultsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func resultsContainer(results []miniv1.TaskResult) corev1.Container {
+
+	var names []string
+	for _, result := range results {
+		names = append(names, result.Name)
+	}
+
+	script := fmt.Sprintf(`cd %s
+for name in %s; do
+  if [ ! -f "$name" ]; then
+    echo "$name missing"
+  elif [ "$(wc -c < "$name")" -gt %d ]; then
+    echo "$name toolarge"
+  else
+    echo "$name value $(base64 -w 0 "$name")"
+  fi
+done > /dev/termination-log
+echo end >> /dev/termination-log
+`, ResultsDir, strings.Join(names, " "), MaxResultSize)
+
+	return corev1.Container{
+		Name:            ResultsContainerName,
+		Image:           HelperImage,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{script},
+		VolumeMounts:    []corev1.VolumeMount{resultsVolumeMount()},
+		SecurityContext: helperSecurityContext(),
+	}
+}
+
+// ParseResults reads the results reported by the collector of a finished
+// pod. It returns nil when the Task declared no results, and a
+// ValidationError when a declared result is missing or too large.
+func ParseResults(pod *corev1.Pod) ([]miniv1.TaskRunResult, error) {
+
+	var message string
+	found := false
+
+	for _, cs := range pod.Status.ContainerStatuses {
+		if cs.Name == ResultsContainerName && cs.State.Terminated != nil {
+			message = cs.State.Terminated.Message
+			found = true
+		}
+	}
+
+	if !found {
+		return nil, nil
+	}
+
+	lines := strings.Split(strings.TrimSpace(message), "\n")
+	if lines[len(lines)-1] != "end" {
+		// the kubelet truncates termination messages beyond 4096 bytes
+		return nil, &ValidationError{
+			Reason:  "ResultsTooLarge",
+			Message: "results exceed the termination message size limit",
+		}
+	}
+
+	var results []miniv1.TaskRunResult
+	var missing, tooLarge []string
+
+	for _, line := range lines[:len(lines)-1] {
+		fields := strings.SplitN(line, " ", 3)
+
+		switch {
+
+		case len(fields) == 2 && fields[1] == "missing":
+			missing = append(missing, fields[0])
+
+		case len(fields) == 2 && fields[1] == "toolarge":
+			tooLarge = append(tooLarge, fields[0])
+
+		case len(fields) == 3 && fields[1] == "value":
+			value, err := base64.StdEncoding.DecodeString(fields[2])
+			if err != nil {
+				return nil, &ValidationError{
+					Reason:  "InvalidResults",
+					Message: fmt.Sprintf("result %q could not be decoded: %v", fields[0], err),
+				}
+			}
+
+			results = append(results, miniv1.TaskRunResult{
+				Name:  fields[0],
+				Value: strings.TrimSuffix(string(value), "\n"),
+			})
+		}
+	}
+
+	if len(missing) > 0 {
+		return results, &ValidationError{
+			Reason:  "MissingResults",
+			Message: "declared results were not written: " + strings.Join(missing, ", "),
+		}
+	}
+
+	if len(tooLarge) > 0 {
+		return results, &ValidationError{
+			Reason:  "ResultsTooLarge",
+			Message: fmt.Sprintf("results larger than %d bytes: %s", MaxResultSize, strings.Join(tooLarge, ", ")),
+		}
+	}
+
+	return results, nil
+}
@@ -0,0 +1,36 @@
+package resources
+
+// sidecars -> native Kubernetes sidecars: init containers with restartPolicy
+//   Always, placed before the first step
+// start -> each later init container (so every step) waits until the
+//   sidecar is started, i.e. its startup probe passed
+// stop -> the kubelet stops sidecars once the last step (the regular
+//   container) exits, so the Pod still completes
+
+import (
+	corev1 "k8s.io/api/core/v1"
+)
+
+const sidecarPrefix = "sidecar-"
+
+// SidecarContainerName returns the container name used for a sidecar
+func SidecarContainerName(name string) string {
+	return sidecarPrefix + name
+}
+
+func sidecarContainer(sidecar corev1.Container) corev1.Container {
+
+	container := *sidecar.DeepCopy()
+	container.Name = SidecarContainerName(sidecar.Name)
+
+	always := corev1.ContainerRestartPolicyAlways
+	container.RestartPolicy = &always
+
+	// steps only wait for the startup probe; use the readiness probe for
+	// it so a sidecar is ready before the first step begins
+	if container.StartupProbe == nil && container.ReadinessProbe != nil {
+		container.StartupProbe = container.ReadinessProbe.DeepCopy()
+	}
+
+	return container
+}
@@ -0,0 +1,133 @@
+package resources
+
+import (
+	"fmt"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// SetPhase moves tr to phase and keeps the Succeeded condition and
+// observedGeneration in step with it. reason and message describe why;
+// reason defaults to the phase itself.
+func SetPhase(tr *miniv1.TaskRun, phase, reason, message string) {
+
+	status := metav1.ConditionUnknown
+	switch phase {
+	case "Succeeded":
+		status = metav1.ConditionTrue
+	case "Failed", "Cancelled":
+		status = metav1.ConditionFalse
+	}
+
+	if reason == "" {
+		reason = phase
+	}
+
+	tr.Status.Phase = phase
+	tr.Status.ObservedGeneration = tr.Generation
+
+	meta.SetStatusCondition(&tr.Status.Conditions, metav1.Condition{
+		Type:               miniv1.TaskRunConditionSucceeded,
+		Status:             status,
+		Reason:             reason,
+		Message:            message,
+		ObservedGeneration: tr.Generation,
+	})
+}
+
+// PodFailure explains why a failed pod failed, preferring the first step
+// that exited non-zero over the pod level reason.
+func PodFailure(pod *corev1.Pod) (reason, message string) {
+
+	for _, step := range StepStates(pod) {
+		if step.ExitCode != nil && *step.ExitCode != 0 {
+			return "StepFailed", fmt.Sprintf("step %q exited with code %d", step.Name, *step.ExitCode)
+		}
+	}
+
+	if pod.Status.Reason != "" {
+		return pod.Status.Reason, pod.Status.Message
+	}
+
+	return "PodFailed", fmt.Sprintf("pod %s failed", pod.Name)
+}
+
+// StepsStarted reports whether any step of pod is running or has
+// terminated. Every step but the last runs as an init container, so the
+// pod stays Pending while the earlier steps run.
+func StepsStarted(pod *corev1.Pod) bool {
+
+	for _, step := range StepStates(pod) {
+		if step.State == "Running" || step.State == "Terminated" {
+			return true
+		}
+	}
+
+	return false
+}
+
+// StepStates reports the state of every step container of pod, in the
+// order the steps were declared.
+func StepStates(pod *corev1.Pod) []miniv1.StepState {
+
+	statuses := map[string]corev1.ContainerStatus{}
+	for _, cs := range pod.Status.InitContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		statuses[cs.Name] = cs
+	}
+
+	var states []miniv1.StepState
+
+	containers := append([]corev1.Container{}, pod.Spec.InitContainers...)
+	containers = append(containers, pod.Spec.Containers...)
+
+	for _, container := range containers {
+		if !strings.HasPrefix(container.Name, stepPrefix) {
+			continue
+		}
+
+		state := miniv1.StepState{
+			Name:          strings.TrimPrefix(container.Name, stepPrefix),
+			ContainerName: container.Name,
+			State:         "Waiting",
+		}
+
+		cs, ok := statuses[container.Name]
+
+		switch {
+
+		case ok && cs.State.Running != nil:
+			state.State = "Running"
+			startTime := cs.State.Running.StartedAt
+			state.StartTime = &startTime
+
+		case ok && cs.State.Terminated != nil:
+			terminated := cs.State.Terminated
+			state.State = "Terminated"
+			exitCode := terminated.ExitCode
+			state.ExitCode = &exitCode
+			state.Reason = terminated.Reason
+			startTime := terminated.StartedAt
+			state.StartTime = &startTime
+			finishTime := terminated.FinishedAt
+			state.FinishTime = &finishTime
+
+		case pod.Status.Phase == corev1.PodFailed:
+			// an earlier step failed, this one never ran
+			state.Reason = "Skipped"
+
+		case ok && cs.State.Waiting != nil:
+			state.Reason = cs.State.Waiting.Reason
+		}
+
+		states = append(states, state)
+	}
+
+	return states
+}
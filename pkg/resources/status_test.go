@@ -0,0 +1,55 @@
+package resources
+
+import (
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestStepsStarted(t *testing.T) {
+
+	pod := func(init corev1.ContainerState) *corev1.Pod {
+		return &corev1.Pod{
+			Spec: corev1.PodSpec{
+				InitContainers: []corev1.Container{{Name: PrepareScriptsContainerName}, {Name: stepPrefix + "build"}},
+				Containers:     []corev1.Container{{Name: stepPrefix + "test"}},
+			},
+			Status: corev1.PodStatus{
+				Phase: corev1.PodPending,
+				InitContainerStatuses: []corev1.ContainerStatus{
+					{Name: PrepareScriptsContainerName, State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}}},
+					{Name: stepPrefix + "build", State: init},
+				},
+			},
+		}
+	}
+
+	tests := []struct {
+		name  string
+		state corev1.ContainerState
+		want  bool
+	}{
+		{
+			name:  "first step waiting",
+			state: corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}},
+		},
+		{
+			name:  "first step running",
+			state: corev1.ContainerState{Running: &corev1.ContainerStateRunning{}},
+			want:  true,
+		},
+		{
+			name:  "first step terminated",
+			state: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{}},
+			want:  true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := StepsStarted(pod(tt.state)); got != tt.want {
+				t.Errorf("StepsStarted() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,115 @@
+package resources
+
+// step -> one container of the Pod
+// script mode -> /bin/sh -c <script>, args become $1, $2, ...
+// shebang script (#!) -> written to $(scripts dir)/<container> by the
+//   prepare-scripts init container and executed directly, so the kernel
+//   runs the named interpreter (python, node, ...)
+// command mode -> command and args as given; without either the image
+//   entrypoint runs, which suits distroless images
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+const (
+	// ScriptsDir holds the shebang scripts of the steps
+	ScriptsDir = "/minitask/scripts"
+
+	// PrepareScriptsContainerName writes the shebang scripts before any step runs
+	PrepareScriptsContainerName = "prepare-scripts"
+
+	scriptsVolumeName = "minitask-scripts"
+)
+
+func hasShebang(step miniv1.Step) bool {
+	return strings.HasPrefix(step.Script, "#!")
+}
+
+// ScriptPath returns where the shebang script of a step is written
+func ScriptPath(stepName string) string {
+	return ScriptsDir + "/" + StepContainerName(stepName)
+}
+
+func scriptsVolume() corev1.Volume {
+	return corev1.Volume{
+		Name: scriptsVolumeName,
+		VolumeSource: corev1.VolumeSource{
+			EmptyDir: &corev1.EmptyDirVolumeSource{},
+		},
+	}
+}
+
+func scriptsVolumeMount(readOnly bool) corev1.VolumeMount {
+	return corev1.VolumeMount{
+		Name:      scriptsVolumeName,
+		MountPath: ScriptsDir,
+		ReadOnly:  readOnly,
+	}
+}
+
+// prepareScriptsContainer writes the shebang scripts of steps into the
+// scripts volume. Scripts travel base64 encoded so no quoting can break them.
+func prepareScriptsContainer(steps []miniv1.Step) corev1.Container {
+
+	var script strings.Builder
+	for _, step := range steps {
+		if !hasShebang(step) {
+			continue
+		}
+		path := ScriptPath(step.Name)
+		encoded := base64.StdEncoding.EncodeToString([]byte(step.Script))
+		fmt.Fprintf(&script, "echo %s | base64 -d > %s\nchmod +x %s\n", encoded, path, path)
+	}
+
+	return corev1.Container{
+		Name:            PrepareScriptsContainerName,
+		Image:           HelperImage,
+		Command:         []string{"/bin/sh", "-c"},
+		Args:            []string{script.String()},
+		VolumeMounts:    []corev1.VolumeMount{scriptsVolumeMount(false)},
+		SecurityContext: helperSecurityContext(),
+	}
+}
+
+// stepContainer turns a step into its container, without the volume
+// mounts the Pod adds for workspaces and results.
+func stepContainer(step miniv1.Step) corev1.Container {
+
+	container := corev1.Container{
+		Name:            StepContainerName(step.Name),
+		Image:           step.Image,
+		Command:         step.Command,
+		Args:            step.Args,
+		Env:             step.Env,
+		EnvFrom:         step.EnvFrom,
+		Resources:       step.Resources,
+		WorkingDir:      step.WorkingDir,
+		ImagePullPolicy: step.ImagePullPolicy,
+		SecurityContext: step.SecurityContext,
+		VolumeMounts:    append([]corev1.VolumeMount{}, step.VolumeMounts...),
+	}
+
+	switch {
+
+	case hasShebang(step):
+		container.Command = []string{ScriptPath(step.Name)}
+		container.VolumeMounts = append(container.VolumeMounts, scriptsVolumeMount(true))
+
+	case step.Script != "":
+		container.Command = []string{"/bin/sh", "-c"}
+		container.Args = []string{step.Script}
+		if len(step.Args) > 0 {
+			// $0 is the step name, the args follow as $1, $2, ...
+			container.Args = append(container.Args, step.Name)
+			container.Args = append(container.Args, step.Args...)
+		}
+	}
+
+	return container
+}
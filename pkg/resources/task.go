@@ -0,0 +1,83 @@
+package resources
+
+import (
+	"fmt"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+)
+
+// ResolveTaskSpec returns the TaskSpec tr runs: its inline taskSpec, or
+// the spec of the Task or ClusterTask named by taskRef, fetched with
+// getTask (namespace of tr) or getClusterTask. Without an explicit kind
+// only Tasks of the namespace are considered. Errors from the getters
+// (e.g. NotFound) are returned unchanged.
+func ResolveTaskSpec(
+	tr *miniv1.TaskRun,
+	getTask func(name string) (*miniv1.Task, error),
+	getClusterTask func(name string) (*miniv1.ClusterTask, error),
+) (*miniv1.TaskSpec, error) {
+
+	if (tr.Spec.TaskRef == "") == (tr.Spec.TaskSpec == nil) {
+		return nil, &ValidationError{
+			Reason:  "InvalidTaskRef",
+			Message: "exactly one of taskRef and taskSpec must be set",
+		}
+	}
+
+	if tr.Spec.TaskSpec != nil {
+		if tr.Spec.Kind != "" {
+			return nil, &ValidationError{
+				Reason:  "InvalidTaskRef",
+				Message: "kind can only be set together with taskRef",
+			}
+		}
+		return tr.Spec.TaskSpec, nil
+	}
+
+	switch tr.Spec.Kind {
+
+	case "", miniv1.TaskKind:
+		task, err := getTask(tr.Spec.TaskRef)
+		if err != nil {
+			return nil, err
+		}
+		return &task.Spec, nil
+
+	case miniv1.ClusterTaskKind:
+		clusterTask, err := getClusterTask(tr.Spec.TaskRef)
+		if err != nil {
+			return nil, err
+		}
+		return &clusterTask.Spec, nil
+	}
+
+	return nil, &ValidationError{
+		Reason:  "InvalidTaskRef",
+		Message: fmt.Sprintf("kind must be %s or %s, got %q", miniv1.TaskKind, miniv1.ClusterTaskKind, tr.Spec.Kind),
+	}
+}
+
+// TaskRefIndex indexes TaskRuns by the Task or ClusterTask they reference,
+// see TaskRefKey
+const TaskRefIndex = "taskRef"
+
+// TaskRefKey returns the index key of a Task (<namespace>/<name>) or
+// ClusterTask (ClusterTask/<name>)
+func TaskRefKey(kind, namespace, name string) string {
+	if kind == miniv1.ClusterTaskKind {
+		return miniv1.ClusterTaskKind + "/" + name
+	}
+	return namespace + "/" + name
+}
+
+// TaskRefIndexFunc is the informer index function for TaskRefIndex.
+// TaskRuns with an inline taskSpec are not indexed.
+func TaskRefIndexFunc(obj interface{}) ([]string, error) {
+
+	tr, ok := obj.(*miniv1.TaskRun)
+	if !ok || tr.Spec.TaskRef == "" {
+		return nil, nil
+	}
+
+	return []string{TaskRefKey(tr.Spec.Kind, tr.Namespace, tr.Spec.TaskRef)}, nil
+}
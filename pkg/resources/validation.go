@@ -0,0 +1,402 @@
+package resources
+
+// field-level checks of Tasks and TaskRuns, shared by the admission webhook
+// (as a field.ErrorList) and the controllers (as a ValidationError, for
+// objects created while the webhook was not installed)
+// names -> step, sidecar and workspace names must give valid container and
+//   volume names; param and result names match namePattern, which keeps
+//   them safe in $(...) references and in the results collector script
+// mounts -> step volumeMounts name a declared volume or the volume of a
+//   workspace (ws-<name>)
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// check is the outcome of one group of checks and the reason a controller
+// fails a run with when it finds errors
+type check struct {
+	reason string
+	errs   field.ErrorList
+}
+
+// ValidateTaskSpec checks steps, sidecars, params, results, workspaces and
+// concurrency of a Task, ClusterTask or inline taskSpec.
+func ValidateTaskSpec(spec *miniv1.TaskSpec, path *field.Path) field.ErrorList {
+	return collect(taskSpecChecks(spec, path))
+}
+
+// ValidateTaskRunSpec checks the task source, params, workspace bindings
+// and run settings of a TaskRun, and its inline taskSpec.
+func ValidateTaskRunSpec(spec *miniv1.TaskRunSpec, path *field.Path) field.ErrorList {
+
+	errs := collect(taskRunSpecChecks(spec, path))
+
+	if spec.TaskRef == "" && spec.TaskSpec != nil {
+		errs = append(errs, ValidateTaskSpec(spec.TaskSpec, path.Child("taskSpec"))...)
+	}
+
+	return errs
+}
+
+// ValidateTask runs the checks of ValidateTaskSpec on the resolved spec of
+// a run and returns the first group that fails as a ValidationError, e.g.
+// with reason InvalidSteps.
+func ValidateTask(spec *miniv1.TaskSpec) error {
+	return firstFailure(taskSpecChecks(spec, field.NewPath("taskSpec")))
+}
+
+// ValidateTaskRun runs the checks of ValidateTaskRunSpec, except for the
+// inline taskSpec, which ValidateTask checks once params are applied.
+func ValidateTaskRun(spec *miniv1.TaskRunSpec) error {
+	return firstFailure(taskRunSpecChecks(spec, field.NewPath("spec")))
+}
+
+func taskSpecChecks(spec *miniv1.TaskSpec, path *field.Path) []check {
+	return []check{
+		{"InvalidSteps", validateSteps(spec.Steps, mountableVolumes(spec), path.Child("steps"))},
+		{"InvalidSidecars", validateSidecars(spec.Sidecars, path.Child("sidecars"))},
+		{"InvalidParams", validateParamSpecs(spec.Params, path.Child("params"))},
+		{"InvalidResults", validateResults(spec.Results, path.Child("results"))},
+		{"InvalidWorkspaces", validateWorkspaces(spec.Workspaces, path.Child("workspaces"))},
+		{"InvalidTimeout", validateTimeout(spec.Timeout, path.Child("timeout"))},
+		{"InvalidConcurrency", validateConcurrency(spec.Concurrency, path.Child("concurrency"))},
+	}
+}
+
+func taskRunSpecChecks(spec *miniv1.TaskRunSpec, path *field.Path) []check {
+	return []check{
+		{"InvalidTaskRef", validateTaskSource(spec, path)},
+		{"InvalidParams", validateParams(spec.Params, path.Child("params"))},
+		{"InvalidWorkspaces", validateBindings(spec.Workspaces, path.Child("workspaces"))},
+		{"InvalidTimeout", validateTimeout(spec.Timeout, path.Child("timeout"))},
+		{"InvalidTaskRun", validateRunSettings(spec, path)},
+		{"InvalidConcurrency", validateConcurrency(spec.Concurrency, path.Child("concurrency"))},
+	}
+}
+
+func collect(checks []check) field.ErrorList {
+	var errs field.ErrorList
+	for _, c := range checks {
+		errs = append(errs, c.errs...)
+	}
+	return errs
+}
+
+func firstFailure(checks []check) error {
+	for _, c := range checks {
+		if len(c.errs) > 0 {
+			return &ValidationError{Reason: c.reason, Message: c.errs.ToAggregate().Error()}
+		}
+	}
+	return nil
+}
+
+// mountableVolumes returns the names of the Pod volumes a step may mount:
+// the volumes declared on spec and those of its workspaces
+func mountableVolumes(spec *miniv1.TaskSpec) map[string]bool {
+
+	volumes := map[string]bool{}
+	for _, volume := range spec.Volumes {
+		volumes[volume.Name] = true
+	}
+	for _, ws := range spec.Workspaces {
+		volumes[WorkspaceVolumeName(ws.Name)] = true
+	}
+
+	return volumes
+}
+
+func validateSteps(steps []miniv1.Step, volumes map[string]bool, path *field.Path) field.ErrorList {
+
+	if len(steps) == 0 {
+		return field.ErrorList{field.Required(path, "a Task needs at least one step")}
+	}
+
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, step := range steps {
+		stepPath := path.Index(i)
+
+		errs = append(errs, validatePrefixedName(step.Name, StepContainerName(step.Name), "container", stepPath.Child("name"))...)
+		if seen[step.Name] {
+			errs = append(errs, field.Duplicate(stepPath.Child("name"), step.Name))
+		}
+		seen[step.Name] = true
+
+		if step.Image == "" {
+			errs = append(errs, field.Required(stepPath.Child("image"), ""))
+		}
+
+		if step.Script != "" && len(step.Command) > 0 {
+			errs = append(errs, field.Invalid(stepPath.Child("command"), step.Command, "cannot be set together with script"))
+		}
+
+		for j, mount := range step.VolumeMounts {
+			if !volumes[mount.Name] {
+				errs = append(errs, field.NotFound(stepPath.Child("volumeMounts").Index(j).Child("name"), mount.Name))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateSidecars(sidecars []corev1.Container, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, sidecar := range sidecars {
+		sidecarPath := path.Index(i)
+
+		errs = append(errs, validatePrefixedName(sidecar.Name, SidecarContainerName(sidecar.Name), "container", sidecarPath.Child("name"))...)
+		if seen[sidecar.Name] {
+			errs = append(errs, field.Duplicate(sidecarPath.Child("name"), sidecar.Name))
+		}
+		seen[sidecar.Name] = true
+
+		if sidecar.Image == "" {
+			errs = append(errs, field.Required(sidecarPath.Child("image"), ""))
+		}
+	}
+
+	return errs
+}
+
+func validateParamSpecs(params []miniv1.ParamSpec, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, param := range params {
+		paramPath := path.Index(i)
+
+		errs = append(errs, validateName(param.Name, paramPath.Child("name"))...)
+		if seen[param.Name] {
+			errs = append(errs, field.Duplicate(paramPath.Child("name"), param.Name))
+		}
+		seen[param.Name] = true
+
+		paramType := param.Type
+		if paramType == "" {
+			paramType = miniv1.ParamTypeString
+		}
+
+		switch paramType {
+		case miniv1.ParamTypeString, miniv1.ParamTypeArray, miniv1.ParamTypeObject:
+		default:
+			errs = append(errs, field.NotSupported(paramPath.Child("type"), param.Type,
+				[]string{string(miniv1.ParamTypeString), string(miniv1.ParamTypeArray), string(miniv1.ParamTypeObject)}))
+			continue
+		}
+
+		if param.Default != nil {
+			defaultType := param.Default.Type
+			if defaultType == "" {
+				defaultType = miniv1.ParamTypeString
+			}
+			if defaultType != paramType {
+				errs = append(errs, field.Invalid(paramPath.Child("default"), defaultType,
+					fmt.Sprintf("must be of type %s", paramType)))
+			}
+		}
+	}
+
+	return errs
+}
+
+func validateResults(results []miniv1.TaskResult, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, result := range results {
+		resultPath := path.Index(i)
+
+		errs = append(errs, validateName(result.Name, resultPath.Child("name"))...)
+		if seen[result.Name] {
+			errs = append(errs, field.Duplicate(resultPath.Child("name"), result.Name))
+		}
+		seen[result.Name] = true
+	}
+
+	return errs
+}
+
+func validateWorkspaces(workspaces []miniv1.WorkspaceDeclaration, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, ws := range workspaces {
+		wsPath := path.Index(i)
+
+		errs = append(errs, validatePrefixedName(ws.Name, WorkspaceVolumeName(ws.Name), "volume", wsPath.Child("name"))...)
+		if seen[ws.Name] {
+			errs = append(errs, field.Duplicate(wsPath.Child("name"), ws.Name))
+		}
+		seen[ws.Name] = true
+	}
+
+	return errs
+}
+
+func validateTaskSource(spec *miniv1.TaskRunSpec, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	switch {
+	case spec.TaskRef == "" && spec.TaskSpec == nil:
+		errs = append(errs, field.Required(path.Child("taskRef"), "one of taskRef and taskSpec must be set"))
+
+	case spec.TaskRef != "" && spec.TaskSpec != nil:
+		errs = append(errs, field.Forbidden(path.Child("taskSpec"), "cannot be set together with taskRef"))
+
+	case spec.TaskSpec != nil && spec.Kind != "":
+		errs = append(errs, field.Forbidden(path.Child("kind"), "can only be set together with taskRef"))
+	}
+
+	switch spec.Kind {
+	case "", miniv1.TaskKind, miniv1.ClusterTaskKind:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("kind"), spec.Kind, []string{miniv1.TaskKind, miniv1.ClusterTaskKind}))
+	}
+
+	return errs
+}
+
+func validateParams(params []miniv1.Param, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, param := range params {
+		paramPath := path.Index(i)
+		if seen[param.Name] {
+			errs = append(errs, field.Duplicate(paramPath.Child("name"), param.Name))
+		}
+		seen[param.Name] = true
+	}
+
+	return errs
+}
+
+func validateBindings(bindings []miniv1.WorkspaceBinding, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	seen := map[string]bool{}
+	for i, binding := range bindings {
+		bindingPath := path.Index(i)
+		if seen[binding.Name] {
+			errs = append(errs, field.Duplicate(bindingPath.Child("name"), binding.Name))
+		}
+		seen[binding.Name] = true
+
+		if n := bindingSources(binding); n != 1 {
+			errs = append(errs, field.Invalid(bindingPath, binding.Name,
+				fmt.Sprintf("must set exactly one volume source, found %d", n)))
+		}
+	}
+
+	return errs
+}
+
+func validateRunSettings(spec *miniv1.TaskRunSpec, path *field.Path) field.ErrorList {
+
+	var errs field.ErrorList
+
+	if spec.Retries < 0 {
+		errs = append(errs, field.Invalid(path.Child("retries"), spec.Retries, "must not be negative"))
+	}
+
+	if spec.Status != "" && spec.Status != miniv1.TaskRunSpecStatusCancelled {
+		errs = append(errs, field.NotSupported(path.Child("status"), spec.Status, []string{miniv1.TaskRunSpecStatusCancelled}))
+	}
+
+	if spec.TTLSecondsAfterFinished != nil && *spec.TTLSecondsAfterFinished < 0 {
+		errs = append(errs, field.Invalid(path.Child("ttlSecondsAfterFinished"), *spec.TTLSecondsAfterFinished, "must not be negative"))
+	}
+
+	return errs
+}
+
+func validateTimeout(timeout *metav1.Duration, path *field.Path) field.ErrorList {
+
+	if timeout != nil && timeout.Duration < 0 {
+		return field.ErrorList{field.Invalid(path, timeout.Duration.String(), "must not be negative")}
+	}
+
+	return nil
+}
+
+func validateConcurrency(concurrency *miniv1.Concurrency, path *field.Path) field.ErrorList {
+
+	if concurrency == nil {
+		return nil
+	}
+
+	var errs field.ErrorList
+
+	if concurrency.Group == "" {
+		errs = append(errs, field.Required(path.Child("group"), ""))
+	}
+
+	if concurrency.MaxRunning < 0 {
+		errs = append(errs, field.Invalid(path.Child("maxRunning"), concurrency.MaxRunning, "must not be negative"))
+	}
+
+	switch concurrency.Policy {
+	case "", miniv1.ConcurrencyQueue, miniv1.ConcurrencyReject, miniv1.ConcurrencyCancelInProgress:
+	default:
+		errs = append(errs, field.NotSupported(path.Child("policy"), concurrency.Policy, []string{
+			string(miniv1.ConcurrencyQueue), string(miniv1.ConcurrencyReject), string(miniv1.ConcurrencyCancelInProgress),
+		}))
+	}
+
+	return errs
+}
+
+// validatePrefixedName checks that name, once turned into the container
+// or volume name full, is a valid DNS label.
+func validatePrefixedName(name, full, kind string, path *field.Path) field.ErrorList {
+
+	if name == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	if msgs := validation.IsDNS1123Label(full); len(msgs) > 0 {
+		return field.ErrorList{field.Invalid(path, name,
+			fmt.Sprintf("%s name %q is invalid: %s", kind, full, strings.Join(msgs, "; ")))}
+	}
+
+	return nil
+}
+
+// validateName checks names used in $(params.name) and
+// $(results.name.path) references, which end at the first "." or ")"
+func validateName(name string, path *field.Path) field.ErrorList {
+
+	if name == "" {
+		return field.ErrorList{field.Required(path, "")}
+	}
+
+	if !namePattern.MatchString(name) {
+		return field.ErrorList{field.Invalid(path, name,
+			"must start with a letter or underscore and contain only letters, digits, '_' and '-'")}
+	}
+
+	return nil
+}
+
+var namePattern = regexp.MustCompile(`^[a-zA-Z_][a-zA-Z0-9_-]*$`)
@@ -0,0 +1,53 @@
+package resources
+
+import (
+	"errors"
+	"testing"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+)
+
+func TestValidateTaskVolumeMounts(t *testing.T) {
+
+	spec := func(mount string) *miniv1.TaskSpec {
+		return &miniv1.TaskSpec{
+			Steps: []miniv1.Step{{
+				Name:         "build",
+				Image:        "busybox",
+				VolumeMounts: []corev1.VolumeMount{{Name: mount, MountPath: "/data"}},
+			}},
+			Volumes:    []corev1.Volume{{Name: "cache"}},
+			Workspaces: []miniv1.WorkspaceDeclaration{{Name: "source"}},
+		}
+	}
+
+	tests := []struct {
+		name   string
+		mount  string
+		reason string
+	}{
+		{name: "declared volume", mount: "cache"},
+		{name: "workspace volume", mount: WorkspaceVolumeName("source")},
+		{name: "undeclared volume", mount: "scratch", reason: "InvalidSteps"},
+		{name: "bare workspace name", mount: "source", reason: "InvalidSteps"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTask(spec(tt.mount))
+
+			if tt.reason == "" {
+				if err != nil {
+					t.Fatalf("ValidateTask() error = %v", err)
+				}
+				return
+			}
+
+			var verr *ValidationError
+			if !errors.As(err, &verr) || verr.Reason != tt.reason {
+				t.Fatalf("ValidateTask() error = %v, want reason %s", err, tt.reason)
+			}
+		})
+	}
+}
@@ -0,0 +1,192 @@
+package resources
+
+// workspaces -> declared by the Task, bound by the TaskRun
+// binding -> becomes a Pod volume, mounted into every step container
+// volumeClaimTemplate -> a PVC created per TaskRun and owned by it
+// $(workspaces.name.path) -> mount path of the workspace
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// WorkspacePath returns where a declared workspace is mounted in steps
+func WorkspacePath(ws miniv1.WorkspaceDeclaration) string {
+	if ws.MountPath != "" {
+		return ws.MountPath
+	}
+	return "/workspace/" + ws.Name
+}
+
+// WorkspacePVCName returns the name of the PVC created from a
+// volumeClaimTemplate binding
+func WorkspacePVCName(tr *miniv1.TaskRun, workspace string) string {
+	return tr.Name + "-" + workspace
+}
+
+// WorkspaceVolumeName returns the name of the Pod volume of a workspace
+func WorkspaceVolumeName(workspace string) string {
+	return "ws-" + workspace
+}
+
+// ValidateWorkspaces checks that every required workspace of spec is bound
+// exactly once to exactly one volume source, and nothing else is bound.
+func ValidateWorkspaces(spec *miniv1.TaskSpec, bindings []miniv1.WorkspaceBinding) error {
+
+	declared := map[string]miniv1.WorkspaceDeclaration{}
+	for _, ws := range spec.Workspaces {
+		declared[ws.Name] = ws
+	}
+
+	bound := map[string]bool{}
+	var unknown []string
+
+	for _, binding := range bindings {
+		if _, ok := declared[binding.Name]; !ok {
+			unknown = append(unknown, binding.Name)
+			continue
+		}
+
+		if bound[binding.Name] {
+			return &ValidationError{
+				Reason:  "InvalidWorkspaces",
+				Message: fmt.Sprintf("workspace %q is bound more than once", binding.Name),
+			}
+		}
+		bound[binding.Name] = true
+
+		if n := bindingSources(binding); n != 1 {
+			return &ValidationError{
+				Reason:  "InvalidWorkspaces",
+				Message: fmt.Sprintf("workspace %q must set exactly one volume source, found %d", binding.Name, n),
+			}
+		}
+	}
+
+	if len(unknown) > 0 {
+		sort.Strings(unknown)
+		return &ValidationError{
+			Reason:  "InvalidWorkspaces",
+			Message: "workspaces not declared by the Task: " + strings.Join(unknown, ", "),
+		}
+	}
+
+	var missing []string
+	for _, ws := range spec.Workspaces {
+		if !ws.Optional && !bound[ws.Name] {
+			missing = append(missing, ws.Name)
+		}
+	}
+
+	if len(missing) > 0 {
+		return &ValidationError{
+			Reason:  "MissingWorkspaces",
+			Message: "required workspaces are not bound: " + strings.Join(missing, ", "),
+		}
+	}
+
+	return nil
+}
+
+func bindingSources(binding miniv1.WorkspaceBinding) int {
+	n := 0
+	if binding.EmptyDir != nil {
+		n++
+	}
+	if binding.PersistentVolumeClaim != nil {
+		n++
+	}
+	if binding.VolumeClaimTemplate != nil {
+		n++
+	}
+	if binding.ConfigMap != nil {
+		n++
+	}
+	if binding.Secret != nil {
+		n++
+	}
+	return n
+}
+
+// MakeWorkspacePVCs builds the PVCs for the volumeClaimTemplate bindings of
+// tr. They are owned by the TaskRun so they go away with it.
+func MakeWorkspacePVCs(tr *miniv1.TaskRun) []*corev1.PersistentVolumeClaim {
+
+	var pvcs []*corev1.PersistentVolumeClaim
+
+	for _, binding := range tr.Spec.Workspaces {
+		if binding.VolumeClaimTemplate == nil {
+			continue
+		}
+
+		pvc := binding.VolumeClaimTemplate.DeepCopy()
+		pvc.Name = WorkspacePVCName(tr, binding.Name)
+		pvc.Namespace = tr.Namespace
+		if pvc.Labels == nil {
+			pvc.Labels = map[string]string{}
+		}
+		pvc.Labels[TaskRunLabel] = tr.Name
+		pvc.OwnerReferences = []metav1.OwnerReference{
+			*metav1.NewControllerRef(
+				tr,
+				miniv1.SchemeGroupVersion.WithKind("TaskRun"),
+			),
+		}
+
+		pvcs = append(pvcs, pvc)
+	}
+
+	return pvcs
+}
+
+// workspaceVolumes returns the Pod volumes and the step volume mounts for
+// the workspaces of spec bound by tr. Unbound optional workspaces are skipped.
+func workspaceVolumes(tr *miniv1.TaskRun, spec *miniv1.TaskSpec) ([]corev1.Volume, []corev1.VolumeMount) {
+
+	bindings := map[string]miniv1.WorkspaceBinding{}
+	for _, binding := range tr.Spec.Workspaces {
+		bindings[binding.Name] = binding
+	}
+
+	var volumes []corev1.Volume
+	var mounts []corev1.VolumeMount
+
+	for _, ws := range spec.Workspaces {
+		binding, ok := bindings[ws.Name]
+		if !ok {
+			continue
+		}
+
+		volume := corev1.Volume{Name: WorkspaceVolumeName(ws.Name)}
+
+		switch {
+		case binding.EmptyDir != nil:
+			volume.EmptyDir = binding.EmptyDir.DeepCopy()
+		case binding.PersistentVolumeClaim != nil:
+			volume.PersistentVolumeClaim = binding.PersistentVolumeClaim.DeepCopy()
+		case binding.VolumeClaimTemplate != nil:
+			volume.PersistentVolumeClaim = &corev1.PersistentVolumeClaimVolumeSource{
+				ClaimName: WorkspacePVCName(tr, ws.Name),
+			}
+		case binding.ConfigMap != nil:
+			volume.ConfigMap = binding.ConfigMap.DeepCopy()
+		case binding.Secret != nil:
+			volume.Secret = binding.Secret.DeepCopy()
+		}
+
+		volumes = append(volumes, volume)
+		mounts = append(mounts, corev1.VolumeMount{
+			Name:      volume.Name,
+			MountPath: WorkspacePath(ws),
+			SubPath:   binding.SubPath,
+			ReadOnly:  ws.ReadOnly,
+		})
+	}
+
+	return volumes, mounts
+}
@@ -0,0 +1,169 @@
+package schedule
+
+// scheduled taskrun -> one TaskRun per schedule time, named
+//   <crontaskrun>-<unix minutes>, labelled with the CronTaskRun and owned by it
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	"github.com/robfig/cron/v3"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	// CronTaskRunLabel is set on scheduled TaskRuns to the owning CronTaskRun
+	CronTaskRunLabel = "minitask.myorg.dev/cronTaskRun"
+
+	// ScheduledTimeAnnotation records the schedule time a TaskRun was created for
+	ScheduledTimeAnnotation = "minitask.myorg.dev/scheduledTime"
+
+	// history kept when the CronTaskRun sets no limits
+	DefaultSuccessfulRunsHistoryLimit = 3
+	DefaultFailedRunsHistoryLimit     = 1
+
+	// maxMissed bounds how many missed schedule times are walked through
+	maxMissed = 100
+)
+
+// Parse parses the schedule of ctr in its time zone: timeZone, else the
+// CRON_TZ= prefix of the schedule, else UTC.
+func Parse(ctr *miniv1.CronTaskRun) (cron.Schedule, error) {
+
+	loc := time.UTC
+	if ctr.Spec.TimeZone != "" {
+		var err error
+		loc, err = time.LoadLocation(ctr.Spec.TimeZone)
+		if err != nil {
+			return nil, &resources.ValidationError{
+				Reason:  "InvalidSchedule",
+				Message: fmt.Sprintf("unknown time zone %q", ctr.Spec.TimeZone),
+			}
+		}
+	}
+
+	sched, err := cron.ParseStandard(ctr.Spec.Schedule)
+	if err != nil {
+		return nil, &resources.ValidationError{
+			Reason:  "InvalidSchedule",
+			Message: fmt.Sprintf("invalid schedule %q: %v", ctr.Spec.Schedule, err),
+		}
+	}
+
+	// ParseStandard takes the zone of a CRON_TZ= or TZ= prefix and the
+	// local zone without one; timeZone wins over the prefix, and UTC is used
+	// when neither is given
+	if spec, ok := sched.(*cron.SpecSchedule); ok {
+		if ctr.Spec.TimeZone != "" || !hasZonePrefix(ctr.Spec.Schedule) {
+			spec.Location = loc
+		}
+	}
+
+	return sched, nil
+}
+
+// hasZonePrefix reports whether schedule names its own time zone
+func hasZonePrefix(schedule string) bool {
+	return strings.HasPrefix(schedule, "CRON_TZ=") || strings.HasPrefix(schedule, "TZ=")
+}
+
+// MostRecent returns the latest schedule time at or before now that has not
+// been run yet, or nil when none is due. Runs are counted from the last
+// schedule time, or from creation, and never from before the starting
+// deadline. missed is the number of due times, including the returned one.
+func MostRecent(ctr *miniv1.CronTaskRun, sched cron.Schedule, now time.Time) (recent *time.Time, missed int) {
+
+	earliest := ctr.CreationTimestamp.Time
+	if ctr.Status.LastScheduleTime != nil {
+		earliest = ctr.Status.LastScheduleTime.Time
+	}
+
+	if ctr.Spec.StartingDeadlineSeconds != nil {
+		deadline := now.Add(-time.Duration(*ctr.Spec.StartingDeadlineSeconds) * time.Second)
+		if deadline.After(earliest) {
+			earliest = deadline
+		}
+	}
+
+	for t := sched.Next(earliest); !t.After(now); t = sched.Next(t) {
+		recent = &t
+		missed++
+
+		// far behind, e.g. after the controller was down: jump ahead
+		if missed >= maxMissed {
+			for next := sched.Next(t); !next.After(now); next = sched.Next(next) {
+				t = next
+			}
+			recent = &t
+			break
+		}
+	}
+
+	return recent, missed
+}
+
+// Expired reports whether the starting deadline for scheduled has passed.
+func Expired(ctr *miniv1.CronTaskRun, scheduled, now time.Time) bool {
+	if ctr.Spec.StartingDeadlineSeconds == nil {
+		return false
+	}
+	return now.Sub(scheduled) > time.Duration(*ctr.Spec.StartingDeadlineSeconds)*time.Second
+}
+
+// TaskRunName returns the name of the TaskRun for the schedule time
+func TaskRunName(ctr *miniv1.CronTaskRun, scheduled time.Time) string {
+	return ctr.Name + "-" + strconv.FormatInt(scheduled.Unix()/60, 10)
+}
+
+// MakeTaskRun builds the TaskRun for the schedule time from the template.
+func MakeTaskRun(ctr *miniv1.CronTaskRun, scheduled time.Time) *miniv1.TaskRun {
+
+	template := ctr.Spec.TaskRunTemplate.DeepCopy()
+
+	labels := template.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[CronTaskRunLabel] = ctr.Name
+
+	annotations := template.Annotations
+	if annotations == nil {
+		annotations = map[string]string{}
+	}
+	annotations[ScheduledTimeAnnotation] = scheduled.UTC().Format(time.RFC3339)
+
+	return &miniv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:        TaskRunName(ctr, scheduled),
+			Namespace:   ctr.Namespace,
+			Labels:      labels,
+			Annotations: annotations,
+			OwnerReferences: []metav1.OwnerReference{
+				*metav1.NewControllerRef(
+					ctr,
+					miniv1.SchemeGroupVersion.WithKind("CronTaskRun"),
+				),
+			},
+		},
+		Spec: template.Spec,
+	}
+}
+
+// HistoryLimits returns the number of successful and failed runs to keep.
+func HistoryLimits(ctr *miniv1.CronTaskRun) (successful, failed int) {
+
+	successful, failed = DefaultSuccessfulRunsHistoryLimit, DefaultFailedRunsHistoryLimit
+
+	if ctr.Spec.SuccessfulRunsHistoryLimit != nil {
+		successful = int(*ctr.Spec.SuccessfulRunsHistoryLimit)
+	}
+	if ctr.Spec.FailedRunsHistoryLimit != nil {
+		failed = int(*ctr.Spec.FailedRunsHistoryLimit)
+	}
+
+	return successful, failed
+}
@@ -0,0 +1,229 @@
+package schedule
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/utils/ptr"
+)
+
+var created = time.Date(2026, 3, 10, 10, 0, 0, 0, time.UTC)
+
+func cronTaskRun(schedule string) *miniv1.CronTaskRun {
+	return &miniv1.CronTaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			Name:              "nightly",
+			Namespace:         "default",
+			CreationTimestamp: metav1.NewTime(created),
+		},
+		Spec: miniv1.CronTaskRunSpec{Schedule: schedule},
+	}
+}
+
+func TestParse(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		schedule string
+		timeZone string
+		after    time.Time
+		want     time.Time
+		reason   string
+	}{
+		{
+			name:     "UTC by default",
+			schedule: "0 9 * * *",
+			after:    created,
+			want:     time.Date(2026, 3, 11, 9, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "time zone",
+			schedule: "0 9 * * *",
+			timeZone: "Europe/Berlin",
+			after:    created,
+			want:     time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "CRON_TZ prefix",
+			schedule: "CRON_TZ=Europe/Berlin 0 9 * * *",
+			after:    created,
+			want:     time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "time zone wins over CRON_TZ",
+			schedule: "CRON_TZ=America/New_York 0 9 * * *",
+			timeZone: "Europe/Berlin",
+			after:    created,
+			want:     time.Date(2026, 3, 11, 8, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "unknown time zone",
+			schedule: "0 9 * * *",
+			timeZone: "Mars/Olympus",
+			reason:   "InvalidSchedule",
+		},
+		{
+			name:     "invalid schedule",
+			schedule: "every day",
+			reason:   "InvalidSchedule",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr := cronTaskRun(tt.schedule)
+			ctr.Spec.TimeZone = tt.timeZone
+
+			sched, err := Parse(ctr)
+
+			if tt.reason != "" {
+				var verr *resources.ValidationError
+				if !errors.As(err, &verr) || verr.Reason != tt.reason {
+					t.Fatalf("Parse() error = %v, want reason %s", err, tt.reason)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			if got := sched.Next(tt.after); !got.Equal(tt.want) {
+				t.Errorf("Next(%s) = %s, want %s", tt.after, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMostRecent(t *testing.T) {
+
+	tests := []struct {
+		name         string
+		schedule     string
+		lastSchedule *time.Time
+		deadline     *int64
+		now          time.Time
+		want         *time.Time
+		missed       int
+	}{
+		{
+			name:     "none due",
+			schedule: "*/5 * * * *",
+			now:      created.Add(4 * time.Minute),
+		},
+		{
+			name:     "one due",
+			schedule: "*/5 * * * *",
+			now:      created.Add(5 * time.Minute),
+			want:     ptr.To(created.Add(5 * time.Minute)),
+			missed:   1,
+		},
+		{
+			name:     "missed runs give the latest",
+			schedule: "*/5 * * * *",
+			now:      created.Add(17 * time.Minute),
+			want:     ptr.To(created.Add(15 * time.Minute)),
+			missed:   3,
+		},
+		{
+			name:         "counted from the last schedule time",
+			schedule:     "*/5 * * * *",
+			lastSchedule: ptr.To(created.Add(10 * time.Minute)),
+			now:          created.Add(17 * time.Minute),
+			want:         ptr.To(created.Add(15 * time.Minute)),
+			missed:       1,
+		},
+		{
+			name:     "starting deadline bounds the missed runs",
+			schedule: "*/5 * * * *",
+			deadline: ptr.To[int64](180),
+			now:      created.Add(17 * time.Minute),
+			want:     ptr.To(created.Add(15 * time.Minute)),
+			missed:   1,
+		},
+		{
+			name:     "starting deadline passed for every run",
+			schedule: "*/5 * * * *",
+			deadline: ptr.To[int64](60),
+			now:      created.Add(17 * time.Minute),
+		},
+		{
+			name:     "far behind jumps to the latest",
+			schedule: "* * * * *",
+			now:      created.Add(1000 * time.Minute),
+			want:     ptr.To(created.Add(1000 * time.Minute)),
+			missed:   maxMissed,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr := cronTaskRun(tt.schedule)
+			ctr.Spec.StartingDeadlineSeconds = tt.deadline
+			if tt.lastSchedule != nil {
+				ctr.Status.LastScheduleTime = &metav1.Time{Time: *tt.lastSchedule}
+			}
+
+			sched, err := Parse(ctr)
+			if err != nil {
+				t.Fatalf("Parse() error = %v", err)
+			}
+
+			got, missed := MostRecent(ctr, sched, tt.now)
+
+			switch {
+			case got == nil && tt.want != nil:
+				t.Errorf("MostRecent() = nil, want %s", tt.want)
+			case got != nil && tt.want == nil:
+				t.Errorf("MostRecent() = %s, want nil", got)
+			case got != nil && !got.Equal(*tt.want):
+				t.Errorf("MostRecent() = %s, want %s", got, tt.want)
+			}
+			if missed != tt.missed {
+				t.Errorf("MostRecent() missed = %d, want %d", missed, tt.missed)
+			}
+		})
+	}
+}
+
+func TestExpired(t *testing.T) {
+
+	scheduled := created.Add(5 * time.Minute)
+
+	tests := []struct {
+		name     string
+		deadline *int64
+		now      time.Time
+		want     bool
+	}{
+		{
+			name: "no deadline",
+			now:  scheduled.Add(24 * time.Hour),
+		},
+		{
+			name:     "within the deadline",
+			deadline: ptr.To[int64](60),
+			now:      scheduled.Add(time.Minute),
+		},
+		{
+			name:     "past the deadline",
+			deadline: ptr.To[int64](60),
+			now:      scheduled.Add(time.Minute + time.Second),
+			want:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctr := cronTaskRun("*/5 * * * *")
+			ctr.Spec.StartingDeadlineSeconds = tt.deadline
+
+			if got := Expired(ctr, scheduled, tt.now); got != tt.want {
+				t.Errorf("Expired() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,203 @@
+package trigger
+
+// trigger -> POST /triggers/<namespace>/<trigger> with a JSON payload
+// signature -> X-Hub-Signature-256: sha256=<hex HMAC-SHA256 of the body>
+// triggered taskrun -> taskRunTemplate plus the mapped params, named
+//   <trigger>-<random> and labelled with the Trigger
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+const (
+	// TriggerLabel is set on triggered TaskRuns to the Trigger
+	TriggerLabel = "minitask.myorg.dev/trigger"
+
+	// SignatureHeader carries the HMAC of the request body
+	SignatureHeader = "X-Hub-Signature-256"
+
+	signaturePrefix = "sha256="
+)
+
+// VerifySignature checks that signature is the HMAC-SHA256 of body keyed
+// with secret, as sent in SignatureHeader.
+func VerifySignature(body, secret []byte, signature string) error {
+
+	if !strings.HasPrefix(signature, signaturePrefix) {
+		return fmt.Errorf("missing or malformed %s header", SignatureHeader)
+	}
+
+	got, err := hex.DecodeString(strings.TrimPrefix(signature, signaturePrefix))
+	if err != nil {
+		return fmt.Errorf("malformed %s header: %v", SignatureHeader, err)
+	}
+
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(body)
+
+	if !hmac.Equal(got, mac.Sum(nil)) {
+		return fmt.Errorf("signature mismatch")
+	}
+
+	return nil
+}
+
+// MakeTaskRun builds the TaskRun for a payload. Trigger params override
+// template params of the same name. A payload that is not JSON, or lacks a
+// field a param refers to, is rejected with reason InvalidPayload.
+func MakeTaskRun(t *miniv1.Trigger, payload []byte) (*miniv1.TaskRun, error) {
+
+	var data interface{}
+	if err := json.Unmarshal(payload, &data); err != nil {
+		return nil, &resources.ValidationError{
+			Reason:  "InvalidPayload",
+			Message: fmt.Sprintf("payload is not JSON: %v", err),
+		}
+	}
+
+	template := t.Spec.TaskRunTemplate.DeepCopy()
+
+	for _, tp := range t.Spec.Params {
+		value, err := Evaluate(tp.Value, data)
+		if err != nil {
+			return nil, &resources.ValidationError{
+				Reason:  "InvalidPayload",
+				Message: fmt.Sprintf("param %q: %v", tp.Name, err),
+			}
+		}
+		template.Spec.Params = setParam(template.Spec.Params, miniv1.Param{Name: tp.Name, Value: value})
+	}
+
+	labels := template.Labels
+	if labels == nil {
+		labels = map[string]string{}
+	}
+	labels[TriggerLabel] = t.Name
+
+	return &miniv1.TaskRun{
+		ObjectMeta: metav1.ObjectMeta{
+			GenerateName: t.Name + "-",
+			Namespace:    t.Namespace,
+			Labels:       labels,
+			Annotations:  template.Annotations,
+		},
+		Spec: template.Spec,
+	}, nil
+}
+
+// Evaluate runs a JSONPath template against the payload. A template that
+// is a single expression selecting several values, such as
+// "{.commits[*].id}", always yields an array param, however many values
+// match, so the param type does not depend on the payload. A single
+// expression selecting one array or object yields an array or object
+// param; anything else is rendered as a string.
+func Evaluate(template string, data interface{}) (miniv1.ParamValue, error) {
+
+	parsed, err := jsonpath.Parse("param", template)
+	if err != nil {
+		return miniv1.ParamValue{}, err
+	}
+
+	jp := jsonpath.New("param")
+	if err := jp.Parse(template); err != nil {
+		return miniv1.ParamValue{}, err
+	}
+
+	results, err := jp.FindResults(data)
+	if err != nil {
+		return miniv1.ParamValue{}, err
+	}
+
+	if len(parsed.Root.Nodes) == 1 && selectsMany(parsed.Root.Nodes[0]) {
+		items := []string{}
+		for _, values := range results {
+			for _, item := range values {
+				items = append(items, scalar(item.Interface()))
+			}
+		}
+		return miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: items}, nil
+	}
+
+	if len(results) == 1 && len(results[0]) == 1 {
+		switch v := results[0][0].Interface().(type) {
+
+		case []interface{}:
+			items := []string{}
+			for _, item := range v {
+				items = append(items, scalar(item))
+			}
+			return miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: items}, nil
+
+		case map[string]interface{}:
+			object := map[string]string{}
+			for key, item := range v {
+				object[key] = scalar(item)
+			}
+			return miniv1.ParamValue{Type: miniv1.ParamTypeObject, ObjectVal: object}, nil
+		}
+	}
+
+	var out bytes.Buffer
+	if err := jp.Execute(&out, data); err != nil {
+		return miniv1.ParamValue{}, err
+	}
+
+	return miniv1.ParamValue{Type: miniv1.ParamTypeString, StringVal: out.String()}, nil
+}
+
+// selectsMany reports whether a JSONPath expression can select any number
+// of values: it has a wildcard, filter, recursive descent, union or slice
+func selectsMany(node jsonpath.Node) bool {
+
+	switch n := node.(type) {
+
+	case *jsonpath.ListNode:
+		for _, child := range n.Nodes {
+			if selectsMany(child) {
+				return true
+			}
+		}
+
+	case *jsonpath.ArrayNode:
+		// a single index [i] has a derived end of i+1
+		return !n.Params[1].Derived
+
+	case *jsonpath.WildcardNode, *jsonpath.FilterNode, *jsonpath.RecursiveNode, *jsonpath.UnionNode:
+		return true
+	}
+
+	return false
+}
+
+// scalar renders a JSON value as a param string; nested values stay JSON
+func scalar(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return v
+	case nil:
+		return ""
+	}
+	raw, _ := json.Marshal(v)
+	return string(raw)
+}
+
+func setParam(params []miniv1.Param, param miniv1.Param) []miniv1.Param {
+	for i := range params {
+		if params[i].Name == param.Name {
+			params[i] = param
+			return params
+		}
+	}
+	return append(params, param)
+}
@@ -0,0 +1,97 @@
+package trigger
+
+import (
+	"encoding/json"
+	"testing"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+func TestEvaluate(t *testing.T) {
+
+	tests := []struct {
+		name     string
+		template string
+		payload  string
+		want     miniv1.ParamValue
+	}{
+		{
+			name:     "wildcard over no commits",
+			template: "{.commits[*].id}",
+			payload:  `{"commits":[]}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: []string{}},
+		},
+		{
+			name:     "wildcard over one commit",
+			template: "{.commits[*].id}",
+			payload:  `{"commits":[{"id":"abc"}]}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: []string{"abc"}},
+		},
+		{
+			name:     "wildcard over two commits",
+			template: "{.commits[*].id}",
+			payload:  `{"commits":[{"id":"abc"},{"id":"def"}]}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: []string{"abc", "def"}},
+		},
+		{
+			name:     "slice",
+			template: "{.commits[0:1].id}",
+			payload:  `{"commits":[{"id":"abc"},{"id":"def"}]}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: []string{"abc"}},
+		},
+		{
+			name:     "filter",
+			template: `{.commits[?(@.author=="alice")].id}`,
+			payload:  `{"commits":[{"id":"abc","author":"bob"},{"id":"def","author":"alice"}]}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: []string{"def"}},
+		},
+		{
+			name:     "single index",
+			template: "{.commits[0].id}",
+			payload:  `{"commits":[{"id":"abc"},{"id":"def"}]}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeString, StringVal: "abc"},
+		},
+		{
+			name:     "field",
+			template: "{.pusher.name}",
+			payload:  `{"pusher":{"name":"alice"}}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeString, StringVal: "alice"},
+		},
+		{
+			name:     "whole array",
+			template: "{.tags}",
+			payload:  `{"tags":["x","y"]}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeArray, ArrayVal: []string{"x", "y"}},
+		},
+		{
+			name:     "whole object",
+			template: "{.pusher}",
+			payload:  `{"pusher":{"name":"alice"}}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeObject, ObjectVal: map[string]string{"name": "alice"}},
+		},
+		{
+			name:     "text around an expression",
+			template: "refs/{.ref}",
+			payload:  `{"ref":"main"}`,
+			want:     miniv1.ParamValue{Type: miniv1.ParamTypeString, StringVal: "refs/main"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var data interface{}
+			if err := json.Unmarshal([]byte(tt.payload), &data); err != nil {
+				t.Fatalf("bad payload: %v", err)
+			}
+
+			got, err := Evaluate(tt.template, data)
+			if err != nil {
+				t.Fatalf("Evaluate() error = %v", err)
+			}
+			if !equality.Semantic.DeepEqual(got, tt.want) {
+				t.Errorf("Evaluate() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,128 @@
+package webhook
+
+// field-level checks are resources.ValidateTaskSpec and
+// resources.ValidateTaskRunSpec, which the controller runs too for objects
+// created while the webhook was not installed; this file adds the update
+// rules and warnings
+
+import (
+	"fmt"
+	"strings"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// ValidateTaskRunUpdate keeps the spec of a TaskRun as it was created.
+// Only spec.status may change, to cancel the run.
+func ValidateTaskRunUpdate(newTr, oldTr *miniv1.TaskRun) field.ErrorList {
+
+	newSpec := newTr.Spec.DeepCopy()
+	oldSpec := oldTr.Spec.DeepCopy()
+	newSpec.Status = ""
+	oldSpec.Status = ""
+
+	if !equality.Semantic.DeepEqual(newSpec, oldSpec) {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec"), "is immutable after creation, except for status: Cancelled")}
+	}
+
+	if oldTr.Spec.Status == miniv1.TaskRunSpecStatusCancelled && newTr.Spec.Status != miniv1.TaskRunSpecStatusCancelled {
+		return field.ErrorList{field.Forbidden(field.NewPath("spec", "status"), "a cancelled TaskRun cannot be resumed")}
+	}
+
+	return nil
+}
+
+// TaskSpecWarnings lists settings of spec that are allowed but risky.
+func TaskSpecWarnings(spec *miniv1.TaskSpec, path *field.Path) []string {
+
+	var warnings []string
+
+	for i, step := range spec.Steps {
+		stepPath := path.Child("steps").Index(i)
+		warnings = append(warnings, imageWarnings(step.Image, stepPath.Child("image"))...)
+		warnings = append(warnings, securityWarnings(step.SecurityContext, stepPath.Child("securityContext"))...)
+	}
+
+	for i, sidecar := range spec.Sidecars {
+		sidecarPath := path.Child("sidecars").Index(i)
+		warnings = append(warnings, imageWarnings(sidecar.Image, sidecarPath.Child("image"))...)
+		warnings = append(warnings, securityWarnings(sidecar.SecurityContext, sidecarPath.Child("securityContext"))...)
+	}
+
+	for i, volume := range spec.Volumes {
+		if volume.HostPath != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: hostPath volumes give steps access to the node filesystem",
+				path.Child("volumes").Index(i).Child("hostPath")))
+		}
+	}
+
+	if spec.Timeout != nil && spec.Timeout.Duration == 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: 0 disables the timeout, a hung step runs forever", path.Child("timeout")))
+	}
+
+	return warnings
+}
+
+// TaskRunSpecWarnings lists settings of spec that are allowed but risky.
+func TaskRunSpecWarnings(spec *miniv1.TaskRunSpec, path *field.Path) []string {
+
+	var warnings []string
+
+	if spec.TaskSpec != nil {
+		warnings = append(warnings, TaskSpecWarnings(spec.TaskSpec, path.Child("taskSpec"))...)
+	}
+
+	if spec.Timeout != nil && spec.Timeout.Duration == 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: 0 disables the timeout, a hung step runs forever", path.Child("timeout")))
+	}
+
+	if spec.Retries > 5 {
+		warnings = append(warnings, fmt.Sprintf("%s: %d retries can hide real failures", path.Child("retries"), spec.Retries))
+	}
+
+	for i, binding := range spec.Workspaces {
+		if binding.Secret != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: every step can read the mounted Secret",
+				path.Child("workspaces").Index(i).Child("secret")))
+		}
+	}
+
+	return warnings
+}
+
+func imageWarnings(image string, path *field.Path) []string {
+
+	if image == "" || strings.Contains(image, "$(") || strings.Contains(image, "@") {
+		return nil
+	}
+
+	// a tag follows the last colon after the last slash
+	name := image[strings.LastIndex(image, "/")+1:]
+	if !strings.Contains(name, ":") || strings.HasSuffix(name, ":latest") {
+		return []string{fmt.Sprintf("%s: image %q is not pinned to a tag or digest, runs may not be reproducible", path, image)}
+	}
+
+	return nil
+}
+
+func securityWarnings(sc *corev1.SecurityContext, path *field.Path) []string {
+
+	if sc == nil {
+		return nil
+	}
+
+	var warnings []string
+
+	if sc.Privileged != nil && *sc.Privileged {
+		warnings = append(warnings, fmt.Sprintf("%s: privileged containers have full access to the node", path.Child("privileged")))
+	}
+
+	if sc.RunAsUser != nil && *sc.RunAsUser == 0 {
+		warnings = append(warnings, fmt.Sprintf("%s: runs as root", path.Child("runAsUser")))
+	}
+
+	return warnings
+}
@@ -0,0 +1,117 @@
+package webhook
+
+// admission -> POST /validate with an admission.k8s.io/v1 AdmissionReview
+// Task, ClusterTask -> ValidateTaskSpec on create and update
+// TaskRun -> ValidateTaskRunSpec on create, ValidateTaskRunUpdate on update
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+
+	miniv1 "github.com/ankrsinha/mini-task/pkg/apis/minitask/v1"
+	"github.com/ankrsinha/mini-task/pkg/resources"
+	admissionv1 "k8s.io/api/admission/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/validation/field"
+)
+
+// maxReviewSize bounds the AdmissionReview bodies the server reads
+const maxReviewSize = 3 << 20
+
+// ServeValidate answers an AdmissionReview.
+func ServeValidate(w http.ResponseWriter, r *http.Request) {
+
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxReviewSize))
+	if err != nil {
+		http.Error(w, "cannot read request", http.StatusBadRequest)
+		return
+	}
+
+	review := admissionv1.AdmissionReview{}
+	if err := json.Unmarshal(body, &review); err != nil || review.Request == nil {
+		http.Error(w, "expected an AdmissionReview", http.StatusBadRequest)
+		return
+	}
+
+	response := Review(review.Request)
+	response.UID = review.Request.UID
+
+	review.Request = nil
+	review.Response = response
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(review)
+}
+
+// Review validates the object of an admission request.
+func Review(req *admissionv1.AdmissionRequest) *admissionv1.AdmissionResponse {
+
+	var errs field.ErrorList
+	var warnings []string
+
+	switch req.Kind.Kind {
+
+	case "Task":
+		task := &miniv1.Task{}
+		if err := json.Unmarshal(req.Object.Raw, task); err != nil {
+			return deny(fmt.Sprintf("cannot decode Task: %v", err))
+		}
+		errs = resources.ValidateTaskSpec(&task.Spec, field.NewPath("spec"))
+		warnings = TaskSpecWarnings(&task.Spec, field.NewPath("spec"))
+
+	case "ClusterTask":
+		clusterTask := &miniv1.ClusterTask{}
+		if err := json.Unmarshal(req.Object.Raw, clusterTask); err != nil {
+			return deny(fmt.Sprintf("cannot decode ClusterTask: %v", err))
+		}
+		errs = resources.ValidateTaskSpec(&clusterTask.Spec, field.NewPath("spec"))
+		warnings = TaskSpecWarnings(&clusterTask.Spec, field.NewPath("spec"))
+
+	case "TaskRun":
+		tr := &miniv1.TaskRun{}
+		if err := json.Unmarshal(req.Object.Raw, tr); err != nil {
+			return deny(fmt.Sprintf("cannot decode TaskRun: %v", err))
+		}
+
+		switch req.Operation {
+		case admissionv1.Update:
+			oldTr := &miniv1.TaskRun{}
+			if err := json.Unmarshal(req.OldObject.Raw, oldTr); err != nil {
+				return deny(fmt.Sprintf("cannot decode old TaskRun: %v", err))
+			}
+			// the spec cannot change, so it is not validated again: rules
+			// added since it was created must not block finalizer and
+			// label updates
+			errs = ValidateTaskRunUpdate(tr, oldTr)
+		default:
+			errs = resources.ValidateTaskRunSpec(&tr.Spec, field.NewPath("spec"))
+			warnings = TaskRunSpecWarnings(&tr.Spec, field.NewPath("spec"))
+		}
+
+	default:
+		// not ours to judge
+		return &admissionv1.AdmissionResponse{Allowed: true}
+	}
+
+	if len(errs) > 0 {
+		response := deny(errs.ToAggregate().Error())
+		response.Warnings = warnings
+		return response
+	}
+
+	return &admissionv1.AdmissionResponse{Allowed: true, Warnings: warnings}
+}
+
+func deny(message string) *admissionv1.AdmissionResponse {
+	return &admissionv1.AdmissionResponse{
+		Allowed: false,
+		Result: &metav1.Status{
+			Status:  metav1.StatusFailure,
+			Reason:  metav1.StatusReasonInvalid,
+			Message: message,
+			Code:    http.StatusUnprocessableEntity,
+		},
+	}
+}